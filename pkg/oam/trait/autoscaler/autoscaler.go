@@ -0,0 +1,144 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errNoMetrics    = "autoscaler must target at least one of CPU or memory utilization"
+	errFmtMinMax    = "autoscaler minimum replicas %d must not exceed maximum replicas %d"
+	errFmtMinTooLow = "autoscaler minimum replicas %d must be at least 1"
+	errNoDeployment = "no deployment found to autoscale"
+)
+
+// defaultMinReplicas is used when a Target does not specify MinReplicas.
+const defaultMinReplicas = 1
+
+var (
+	deploymentKind       = reflect.TypeOf(appsv1.Deployment{}).Name()
+	deploymentAPIVersion = appsv1.SchemeGroupVersion.String()
+
+	hpaKind       = reflect.TypeOf(autoscalingv2beta2.HorizontalPodAutoscaler{}).Name()
+	hpaAPIVersion = autoscalingv2beta2.SchemeGroupVersion.String()
+)
+
+// A Target configures how a workload should be autoscaled.
+type Target struct {
+	// MinReplicas the workload may be scaled down to. Must be at least 1.
+	// Defaults to 1.
+	MinReplicas *int32
+
+	// MaxReplicas the workload may be scaled up to.
+	MaxReplicas int32
+
+	// CPUUtilization is the target average CPU utilization of the workload,
+	// as a percentage of its requested CPU.
+	CPUUtilization *int32
+
+	// MemoryUtilization is the target average memory utilization of the
+	// workload, as a percentage of its requested memory.
+	MemoryUtilization *int32
+}
+
+// ApplyAutoscaler appends a HorizontalPodAutoscaler to the supplied objects
+// for each Deployment they contain. Each HorizontalPodAutoscaler is named
+// after and scales the Deployment it targets. The replicas of each
+// autoscaled Deployment are cleared so that they are owned by its
+// HorizontalPodAutoscaler.
+func ApplyAutoscaler(t Target, objs []resource.Object) ([]resource.Object, error) {
+	minReplicas := int32(defaultMinReplicas)
+	if t.MinReplicas != nil {
+		minReplicas = *t.MinReplicas
+	}
+	if minReplicas < 1 {
+		return nil, errors.Errorf(errFmtMinTooLow, minReplicas)
+	}
+	if minReplicas > t.MaxReplicas {
+		return nil, errors.Errorf(errFmtMinMax, minReplicas, t.MaxReplicas)
+	}
+
+	metrics := make([]autoscalingv2beta2.MetricSpec, 0, 2)
+	if t.CPUUtilization != nil {
+		metrics = append(metrics, utilization(corev1.ResourceCPU, *t.CPUUtilization))
+	}
+	if t.MemoryUtilization != nil {
+		metrics = append(metrics, utilization(corev1.ResourceMemory, *t.MemoryUtilization))
+	}
+	if len(metrics) == 0 {
+		return nil, errors.New(errNoMetrics)
+	}
+
+	var hpas []resource.Object
+	for _, o := range objs {
+		d, ok := o.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+		d.Spec.Replicas = nil
+
+		hpas = append(hpas, &autoscalingv2beta2.HorizontalPodAutoscaler{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       hpaKind,
+				APIVersion: hpaAPIVersion,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      d.GetName(),
+				Namespace: d.GetNamespace(),
+			},
+			Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{
+					Kind:       deploymentKind,
+					Name:       d.GetName(),
+					APIVersion: deploymentAPIVersion,
+				},
+				MinReplicas: &minReplicas,
+				MaxReplicas: t.MaxReplicas,
+				Metrics:     metrics,
+			},
+		})
+	}
+	if len(hpas) == 0 {
+		return nil, errors.New(errNoDeployment)
+	}
+
+	return append(objs, hpas...), nil
+}
+
+// utilization returns a metric that targets the supplied average utilization
+// percentage of the supplied resource.
+func utilization(name corev1.ResourceName, percent int32) autoscalingv2beta2.MetricSpec {
+	return autoscalingv2beta2.MetricSpec{
+		Type: autoscalingv2beta2.ResourceMetricSourceType,
+		Resource: &autoscalingv2beta2.ResourceMetricSource{
+			Name: name,
+			Target: autoscalingv2beta2.MetricTarget{
+				Type:               autoscalingv2beta2.UtilizationMetricType,
+				AverageUtilization: &percent,
+			},
+		},
+	}
+}
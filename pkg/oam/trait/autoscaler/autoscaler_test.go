@@ -0,0 +1,172 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var (
+	deploymentName      = "cool-deployment"
+	deploymentNamespace = "cool-namespace"
+	replicas            = int32(3)
+)
+
+func deployment(r *int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       deploymentKind,
+			APIVersion: deploymentAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deploymentName,
+			Namespace: deploymentNamespace,
+		},
+		Spec: appsv1.DeploymentSpec{Replicas: r},
+	}
+}
+
+func int32Ptr(i int32) *int32 { return &i }
+
+func TestApplyAutoscaler(t *testing.T) {
+	type args struct {
+		t    Target
+		objs []resource.Object
+	}
+
+	type want struct {
+		objs []resource.Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrorMinExceedsMax": {
+			reason: "A minimum replica count greater than the maximum should return an error.",
+			args: args{
+				t:    Target{MinReplicas: int32Ptr(5), MaxReplicas: 2, CPUUtilization: int32Ptr(80)},
+				objs: []resource.Object{deployment(&replicas)},
+			},
+			want: want{err: errors.Errorf(errFmtMinMax, 5, 2)},
+		},
+		"ErrorMinTooLow": {
+			reason: "A minimum replica count less than 1 should return an error.",
+			args: args{
+				t:    Target{MinReplicas: int32Ptr(0), MaxReplicas: 2, CPUUtilization: int32Ptr(80)},
+				objs: []resource.Object{deployment(&replicas)},
+			},
+			want: want{err: errors.Errorf(errFmtMinTooLow, 0)},
+		},
+		"ErrorNoMetrics": {
+			reason: "A target with no metrics should return an error.",
+			args: args{
+				t:    Target{MaxReplicas: 2},
+				objs: []resource.Object{deployment(&replicas)},
+			},
+			want: want{err: errors.New(errNoMetrics)},
+		},
+		"ErrorNoDeployment": {
+			reason: "Objects without a Deployment should return an error.",
+			args: args{
+				t:    Target{MaxReplicas: 2, CPUUtilization: int32Ptr(80)},
+				objs: []resource.Object{&corev1.Service{}},
+			},
+			want: want{err: errors.New(errNoDeployment)},
+		},
+		"Success": {
+			reason: "A HorizontalPodAutoscaler targeting the Deployment should be appended.",
+			args: args{
+				t: Target{
+					MaxReplicas:       10,
+					CPUUtilization:    int32Ptr(80),
+					MemoryUtilization: int32Ptr(70),
+				},
+				objs: []resource.Object{deployment(&replicas)},
+			},
+			want: want{objs: []resource.Object{
+				deployment(nil),
+				&autoscalingv2beta2.HorizontalPodAutoscaler{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "HorizontalPodAutoscaler",
+						APIVersion: "autoscaling/v2beta2",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      deploymentName,
+						Namespace: deploymentNamespace,
+					},
+					Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+						ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{
+							Kind:       "Deployment",
+							Name:       deploymentName,
+							APIVersion: "apps/v1",
+						},
+						MinReplicas: int32Ptr(defaultMinReplicas),
+						MaxReplicas: 10,
+						Metrics: []autoscalingv2beta2.MetricSpec{
+							{
+								Type: autoscalingv2beta2.ResourceMetricSourceType,
+								Resource: &autoscalingv2beta2.ResourceMetricSource{
+									Name: corev1.ResourceCPU,
+									Target: autoscalingv2beta2.MetricTarget{
+										Type:               autoscalingv2beta2.UtilizationMetricType,
+										AverageUtilization: int32Ptr(80),
+									},
+								},
+							},
+							{
+								Type: autoscalingv2beta2.ResourceMetricSourceType,
+								Resource: &autoscalingv2beta2.ResourceMetricSource{
+									Name: corev1.ResourceMemory,
+									Target: autoscalingv2beta2.MetricTarget{
+										Type:               autoscalingv2beta2.UtilizationMetricType,
+										AverageUtilization: int32Ptr(70),
+									},
+								},
+							},
+						},
+					},
+				},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			objs, err := ApplyAutoscaler(tc.args.t, tc.args.objs)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nApplyAutoscaler(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, objs); diff != "" {
+				t.Errorf("\nReason: %s\nApplyAutoscaler(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/crossplane/pkg/oam/workload"
+)
+
+const (
+	errNoService  = "no workload service found to expose"
+	errNilService = "cannot expose a nil service"
+	errFmtNoPorts = "service %q exposes no ports"
+	errNoHost     = "ingress host must be specified"
+)
+
+// defaultPathPrefix is used when a Route does not specify a PathPrefix.
+const defaultPathPrefix = "/"
+
+var (
+	ingressKind       = reflect.TypeOf(networkingv1beta1.Ingress{}).Name()
+	ingressAPIVersion = networkingv1beta1.SchemeGroupVersion.String()
+)
+
+// An Option configures ApplyIngress.
+type Option func(*options)
+
+type options struct {
+	labelKey string
+}
+
+// WithLabelKey configures the label by which a Service must select pods to be
+// considered the workload's Service. workload.LabelKey is used by default.
+func WithLabelKey(k string) Option {
+	return func(o *options) {
+		o.labelKey = k
+	}
+}
+
+// newOptions returns the default options, modified by the supplied Options.
+func newOptions(o ...Option) options {
+	opts := options{labelKey: workload.LabelKey}
+	for _, fn := range o {
+		fn(&opts)
+	}
+	return opts
+}
+
+// A Route configures how a workload should be exposed.
+type Route struct {
+	// Host at which the workload should be exposed, e.g. 'example.org'.
+	Host string
+
+	// PathPrefix at which the workload should be exposed. Defaults to '/'.
+	PathPrefix string
+
+	// TLSSecretName is the name of a Secret containing the TLS certificate
+	// and key for Host. TLS is not configured if it is empty.
+	TLSSecretName string
+}
+
+// ApplyIngress appends an Ingress to the supplied objects that routes the
+// supplied host and path prefix to the first port of the supplied workload's
+// Service. The workload's Service is the first Service that selects pods
+// labelled with the workload's UID using workload.LabelKey, or the label key
+// configured by WithLabelKey, such as that added by workload.ServiceInjector.
+// The Ingress is named after the Service.
+func ApplyIngress(w resource.Workload, r Route, objs []resource.Object, o ...Option) ([]resource.Object, error) {
+	opts := newOptions(o...)
+	if r.Host == "" {
+		return nil, errors.New(errNoHost)
+	}
+
+	svc, err := workloadService(w, opts.labelKey, objs)
+	if err != nil {
+		return nil, err
+	}
+	if len(svc.Spec.Ports) == 0 {
+		return nil, errors.Errorf(errFmtNoPorts, svc.GetName())
+	}
+
+	path := r.PathPrefix
+	if path == "" {
+		path = defaultPathPrefix
+	}
+
+	i := &networkingv1beta1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       ingressKind,
+			APIVersion: ingressAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      svc.GetName(),
+			Namespace: svc.GetNamespace(),
+		},
+		Spec: networkingv1beta1.IngressSpec{
+			Rules: []networkingv1beta1.IngressRule{{
+				Host: r.Host,
+				IngressRuleValue: networkingv1beta1.IngressRuleValue{
+					HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+						Paths: []networkingv1beta1.HTTPIngressPath{{
+							Path: path,
+							Backend: networkingv1beta1.IngressBackend{
+								ServiceName: svc.GetName(),
+								ServicePort: intstr.FromInt(int(svc.Spec.Ports[0].Port)),
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}
+	if r.TLSSecretName != "" {
+		i.Spec.TLS = []networkingv1beta1.IngressTLS{{
+			Hosts:      []string{r.Host},
+			SecretName: r.TLSSecretName,
+		}}
+	}
+
+	return append(objs, i), nil
+}
+
+// workloadService returns the first Service in the supplied objects that
+// selects pods labelled with the supplied workload's UID using the supplied
+// label key. It returns an error if there is no such Service, or if a nil
+// Service is encountered before it.
+func workloadService(w resource.Workload, labelKey string, objs []resource.Object) (*corev1.Service, error) {
+	for _, o := range objs {
+		s, ok := o.(*corev1.Service)
+		if !ok {
+			continue
+		}
+		if s == nil {
+			return nil, errors.New(errNilService)
+		}
+		if v, ok := s.Spec.Selector[labelKey]; ok && v == string(w.GetUID()) {
+			return s, nil
+		}
+	}
+	return nil, errors.New(errNoService)
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/pkg/oam/workload"
+)
+
+var (
+	serviceName      = "cool-svc"
+	serviceNamespace = "cool-namespace"
+	workloadUID      = "a-very-unique-identifier"
+)
+
+type serviceModifier func(*corev1.Service)
+
+func sWithPort(p int32) serviceModifier {
+	return func(s *corev1.Service) {
+		s.Spec.Ports = append(s.Spec.Ports, corev1.ServicePort{Port: p})
+	}
+}
+
+func sWithSelector(sel map[string]string) serviceModifier {
+	return func(s *corev1.Service) {
+		s.Spec.Selector = sel
+	}
+}
+
+func service(mod ...serviceModifier) *corev1.Service {
+	s := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serviceName,
+			Namespace: serviceNamespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{workload.LabelKey: workloadUID},
+		},
+	}
+	for _, m := range mod {
+		m(s)
+	}
+	return s
+}
+
+type ingressModifier func(*networkingv1beta1.Ingress)
+
+func iWithTLS(secret string) ingressModifier {
+	return func(i *networkingv1beta1.Ingress) {
+		i.Spec.TLS = []networkingv1beta1.IngressTLS{{
+			Hosts:      []string{i.Spec.Rules[0].Host},
+			SecretName: secret,
+		}}
+	}
+}
+
+func ingress(host, path string, port int, mod ...ingressModifier) *networkingv1beta1.Ingress {
+	i := &networkingv1beta1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Ingress",
+			APIVersion: "networking.k8s.io/v1beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serviceName,
+			Namespace: serviceNamespace,
+		},
+		Spec: networkingv1beta1.IngressSpec{
+			Rules: []networkingv1beta1.IngressRule{{
+				Host: host,
+				IngressRuleValue: networkingv1beta1.IngressRuleValue{
+					HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+						Paths: []networkingv1beta1.HTTPIngressPath{{
+							Path: path,
+							Backend: networkingv1beta1.IngressBackend{
+								ServiceName: serviceName,
+								ServicePort: intstr.FromInt(port),
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}
+	for _, m := range mod {
+		m(i)
+	}
+	return i
+}
+
+func TestApplyIngress(t *testing.T) {
+	w := &fake.Workload{ObjectMeta: metav1.ObjectMeta{Name: "cool-workload", UID: types.UID(workloadUID)}}
+
+	type args struct {
+		r    Route
+		objs []resource.Object
+		o    []Option
+	}
+
+	type want struct {
+		objs []resource.Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrorNoHost": {
+			reason: "A route without a host should return an error.",
+			args: args{
+				objs: []resource.Object{service(sWithPort(80))},
+			},
+			want: want{err: errors.New(errNoHost)},
+		},
+		"ErrorNoService": {
+			reason: "Objects without a workload Service should return an error.",
+			args: args{
+				r:    Route{Host: "example.org"},
+				objs: []resource.Object{&appsv1.Deployment{}, &corev1.Service{}},
+			},
+			want: want{err: errors.New(errNoService)},
+		},
+		"ErrorNilService": {
+			reason: "A nil Service should return an error rather than panic.",
+			args: args{
+				r:    Route{Host: "example.org"},
+				objs: []resource.Object{(*corev1.Service)(nil), service(sWithPort(80))},
+			},
+			want: want{err: errors.New(errNilService)},
+		},
+		"ErrorNoPorts": {
+			reason: "A workload Service without ports should return an error.",
+			args: args{
+				r:    Route{Host: "example.org"},
+				objs: []resource.Object{service()},
+			},
+			want: want{err: errors.Errorf(errFmtNoPorts, serviceName)},
+		},
+		"SuccessDefaultPath": {
+			reason: "An Ingress routing the root path to the Service's first port should be appended.",
+			args: args{
+				r:    Route{Host: "example.org"},
+				objs: []resource.Object{service(sWithPort(80), sWithPort(443))},
+			},
+			want: want{objs: []resource.Object{
+				service(sWithPort(80), sWithPort(443)),
+				ingress("example.org", "/", 80),
+			}},
+		},
+		"ErrorOtherWorkloadService": {
+			reason: "A Service that selects the pods of another workload should not be exposed.",
+			args: args{
+				r:    Route{Host: "example.org"},
+				objs: []resource.Object{service(sWithPort(80), sWithSelector(map[string]string{workload.LabelKey: "another-unique-identifier"}))},
+			},
+			want: want{err: errors.New(errNoService)},
+		},
+		"SuccessTwoWorkloadServices": {
+			reason: "The Service that selects the workload's pods should be exposed, even if another workload's Service precedes it.",
+			args: args{
+				r: Route{Host: "example.org"},
+				objs: []resource.Object{
+					service(sWithPort(8080), sWithSelector(map[string]string{workload.LabelKey: "another-unique-identifier"})),
+					service(sWithPort(80)),
+				},
+			},
+			want: want{objs: []resource.Object{
+				service(sWithPort(8080), sWithSelector(map[string]string{workload.LabelKey: "another-unique-identifier"})),
+				service(sWithPort(80)),
+				ingress("example.org", "/", 80),
+			}},
+		},
+		"ErrorCustomLabelKeyNoService": {
+			reason: "A Service that selects pods by the default label key should not be exposed when another label key is configured.",
+			args: args{
+				r:    Route{Host: "example.org"},
+				objs: []resource.Object{service(sWithPort(80))},
+				o:    []Option{WithLabelKey("example.org/workload")},
+			},
+			want: want{err: errors.New(errNoService)},
+		},
+		"SuccessCustomLabelKey": {
+			reason: "A Service that selects pods by the configured label key should be exposed.",
+			args: args{
+				r:    Route{Host: "example.org"},
+				objs: []resource.Object{service(sWithPort(80), sWithSelector(map[string]string{"example.org/workload": workloadUID}))},
+				o:    []Option{WithLabelKey("example.org/workload")},
+			},
+			want: want{objs: []resource.Object{
+				service(sWithPort(80), sWithSelector(map[string]string{"example.org/workload": workloadUID})),
+				ingress("example.org", "/", 80),
+			}},
+		},
+		"SuccessPathAndTLS": {
+			reason: "An Ingress routing the path prefix and terminating TLS should be appended.",
+			args: args{
+				r:    Route{Host: "example.org", PathPrefix: "/api", TLSSecretName: "cool-tls"},
+				objs: []resource.Object{service(sWithPort(8080))},
+			},
+			want: want{objs: []resource.Object{
+				service(sWithPort(8080)),
+				ingress("example.org", "/api", 8080, iWithTLS("cool-tls")),
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			objs, err := ApplyIngress(w, tc.args.r, tc.args.objs, tc.args.o...)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nApplyIngress(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, objs); diff != "" {
+				t.Errorf("\nReason: %s\nApplyIngress(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -48,3 +48,25 @@ func DeploymentModifier(ctx context.Context, obj runtime.Object, t resource.Trai
 
 	return nil
 }
+
+// ApplyManualScaler sets the replicas of any Deployments and StatefulSets in
+// the supplied objects to the replica count of the supplied ManualScalerTrait.
+// Objects without replicas, such as Services, are returned unmodified.
+func ApplyManualScaler(t resource.Trait, objs []resource.Object) ([]resource.Object, error) {
+	ms, ok := t.(*oamv1alpha2.ManualScalerTrait)
+	if !ok {
+		return nil, errors.New(errNotManualScalerTrait)
+	}
+
+	for _, o := range objs {
+		r := ms.Spec.ReplicaCount
+		switch obj := o.(type) {
+		case *appsv1.Deployment:
+			obj.Spec.Replicas = &r
+		case *appsv1.StatefulSet:
+			obj.Spec.Replicas = &r
+		}
+	}
+
+	return objs, nil
+}
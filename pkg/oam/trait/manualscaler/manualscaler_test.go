@@ -23,6 +23,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/trait"
@@ -97,3 +98,66 @@ func TestDeploymentModifier(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyManualScaler(t *testing.T) {
+	scaled := int32(3)
+
+	type args struct {
+		t    resource.Trait
+		objs []resource.Object
+	}
+
+	type want struct {
+		objs []resource.Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrorTraitNotManualScaler": {
+			reason: "Trait passed to applier that is not a ManualScalerTrait should return error.",
+			args: args{
+				t: &fake.Trait{},
+			},
+			want: want{err: errors.New(errNotManualScalerTrait)},
+		},
+		"Success": {
+			reason: "Deployments and StatefulSets should be scaled, and Services left untouched.",
+			args: args{
+				t: &oamv1alpha2.ManualScalerTrait{
+					Spec: oamv1alpha2.ManualScalerTraitSpec{
+						ReplicaCount: scaled,
+					},
+				},
+				objs: []resource.Object{
+					&appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: &startingReplicas}},
+					&appsv1.StatefulSet{},
+					&corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP}},
+				},
+			},
+			want: want{
+				objs: []resource.Object{
+					&appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: &scaled}},
+					&appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{Replicas: &scaled}},
+					&corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			objs, err := ApplyManualScaler(tc.args.t, tc.args.objs)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nApplyManualScaler(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, objs); diff != "" {
+				t.Errorf("\nReason: %s\nApplyManualScaler(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
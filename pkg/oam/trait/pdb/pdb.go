@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdb
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/crossplane/pkg/oam/workload"
+)
+
+const (
+	errBothMinAndMax    = "pod disruption budget may specify only one of minAvailable or maxUnavailable"
+	errNeitherMinNorMax = "pod disruption budget must specify one of minAvailable or maxUnavailable"
+)
+
+// NameSuffix is appended to the name of a workload to produce the name of its
+// PodDisruptionBudget.
+const NameSuffix = "-pdb"
+
+var (
+	pdbKind       = reflect.TypeOf(policyv1beta1.PodDisruptionBudget{}).Name()
+	pdbAPIVersion = policyv1beta1.SchemeGroupVersion.String()
+)
+
+// An Option configures ApplyPodDisruptionBudget.
+type Option func(*options)
+
+type options struct {
+	labelKey string
+}
+
+// WithLabelKey configures the label the PodDisruptionBudget uses to select the
+// workload's pods. workload.LabelKey is used by default.
+func WithLabelKey(k string) Option {
+	return func(o *options) {
+		o.labelKey = k
+	}
+}
+
+// newOptions returns the default options, modified by the supplied Options.
+func newOptions(o ...Option) options {
+	opts := options{labelKey: workload.LabelKey}
+	for _, fn := range o {
+		fn(&opts)
+	}
+	return opts
+}
+
+// A Budget limits how many of a workload's pods may be voluntarily disrupted
+// at once. Exactly one of MinAvailable or MaxUnavailable must be specified.
+type Budget struct {
+	// MinAvailable pods that must remain after an eviction, as an absolute
+	// number or a percentage.
+	MinAvailable *intstr.IntOrString
+
+	// MaxUnavailable pods that may be unavailable after an eviction, as an
+	// absolute number or a percentage.
+	MaxUnavailable *intstr.IntOrString
+}
+
+// ApplyPodDisruptionBudget appends a PodDisruptionBudget to the supplied
+// objects that selects the pods of the supplied workload by workload.LabelKey,
+// or the label key configured by WithLabelKey.
+func ApplyPodDisruptionBudget(w resource.Workload, b Budget, objs []resource.Object, o ...Option) ([]resource.Object, error) {
+	opts := newOptions(o...)
+	if b.MinAvailable != nil && b.MaxUnavailable != nil {
+		return nil, errors.New(errBothMinAndMax)
+	}
+	if b.MinAvailable == nil && b.MaxUnavailable == nil {
+		return nil, errors.New(errNeitherMinNorMax)
+	}
+
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       pdbKind,
+			APIVersion: pdbAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      w.GetName() + NameSuffix,
+			Namespace: w.GetNamespace(),
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MinAvailable:   b.MinAvailable,
+			MaxUnavailable: b.MaxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					opts.labelKey: string(w.GetUID()),
+				},
+			},
+		},
+	}
+
+	return append(objs, pdb), nil
+}
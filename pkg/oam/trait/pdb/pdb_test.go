@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdb
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/pkg/oam/workload"
+)
+
+var (
+	workloadName      = "cool-workload"
+	workloadNamespace = "cool-namespace"
+	workloadUID       = "a-very-unique-identifier"
+)
+
+func pdb(spec policyv1beta1.PodDisruptionBudgetSpec) *policyv1beta1.PodDisruptionBudget {
+	spec.Selector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{workload.LabelKey: workloadUID},
+	}
+	return &policyv1beta1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PodDisruptionBudget",
+			APIVersion: "policy/v1beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName + "-pdb",
+			Namespace: workloadNamespace,
+		},
+		Spec: spec,
+	}
+}
+
+func TestApplyPodDisruptionBudget(t *testing.T) {
+	two := intstr.FromInt(2)
+	quarter := intstr.FromString("25%")
+
+	w := &fake.Workload{ObjectMeta: metav1.ObjectMeta{
+		Name:      workloadName,
+		Namespace: workloadNamespace,
+		UID:       types.UID(workloadUID),
+	}}
+
+	customKey := "example.org/workload"
+	custom := pdb(policyv1beta1.PodDisruptionBudgetSpec{MinAvailable: &two})
+	custom.Spec.Selector.MatchLabels = map[string]string{customKey: workloadUID}
+
+	type args struct {
+		b    Budget
+		objs []resource.Object
+		o    []Option
+	}
+
+	type want struct {
+		objs []resource.Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrorBoth": {
+			reason: "A budget specifying both minAvailable and maxUnavailable should return an error.",
+			args: args{
+				b: Budget{MinAvailable: &two, MaxUnavailable: &quarter},
+			},
+			want: want{err: errors.New(errBothMinAndMax)},
+		},
+		"ErrorNeither": {
+			reason: "A budget specifying neither minAvailable nor maxUnavailable should return an error.",
+			args: args{
+				b: Budget{},
+			},
+			want: want{err: errors.New(errNeitherMinNorMax)},
+		},
+		"SuccessfulMinAvailable": {
+			reason: "A PodDisruptionBudget with minAvailable should be appended.",
+			args: args{
+				b:    Budget{MinAvailable: &two},
+				objs: []resource.Object{&appsv1.Deployment{}},
+			},
+			want: want{objs: []resource.Object{
+				&appsv1.Deployment{},
+				pdb(policyv1beta1.PodDisruptionBudgetSpec{MinAvailable: &two}),
+			}},
+		},
+		"SuccessfulMaxUnavailable": {
+			reason: "A PodDisruptionBudget with maxUnavailable should be appended.",
+			args: args{
+				b:    Budget{MaxUnavailable: &quarter},
+				objs: []resource.Object{&appsv1.Deployment{}},
+			},
+			want: want{objs: []resource.Object{
+				&appsv1.Deployment{},
+				pdb(policyv1beta1.PodDisruptionBudgetSpec{MaxUnavailable: &quarter}),
+			}},
+		},
+		"SuccessfulCustomLabelKey": {
+			reason: "A PodDisruptionBudget that selects pods by the configured label key should be appended.",
+			args: args{
+				b:    Budget{MinAvailable: &two},
+				objs: []resource.Object{&appsv1.Deployment{}},
+				o:    []Option{WithLabelKey(customKey)},
+			},
+			want: want{objs: []resource.Object{&appsv1.Deployment{}, custom}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			objs, err := ApplyPodDisruptionBudget(w, tc.args.b, tc.args.objs, tc.args.o...)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nApplyPodDisruptionBudget(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, objs); diff != "" {
+				t.Errorf("\nReason: %s\nApplyPodDisruptionBudget(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/crossplane/pkg/oam/workload"
+)
+
+const (
+	errEmptyAntiAffinityTopologyKey = "anti-affinity rule must specify a topology key"
+	errFmtInvalidAntiAffinityWeight = "anti-affinity rule for key %q: weight must be between 1 and 100, got %d"
+	errFmtRequiredWithWeight        = "anti-affinity rule for key %q: a weight may only be set for preferred rules"
+)
+
+// defaultAntiAffinityWeight is the weight of a preferred anti-affinity rule
+// that does not specify one.
+const defaultAntiAffinityWeight = 100
+
+// An AntiAffinity rule spreads a workload's pods across a topology, such as
+// zones or nodes, by discouraging or forbidding more than one of its pods
+// from being scheduled to the same topology domain.
+type AntiAffinity struct {
+	// TopologyKey is the node label that identifies a topology domain, for
+	// example kubernetes.io/hostname.
+	TopologyKey string
+
+	// Required rules forbid two of the workload's pods from being scheduled
+	// to the same topology domain, leaving pods pending if there are not
+	// enough domains. Other rules are preferred, but not enforced.
+	Required bool
+
+	// Weight of a preferred rule, between 1 and 100, relative to the other
+	// preferred scheduling rules of the pod. It defaults to 100, and may not
+	// be set for required rules.
+	Weight int32
+}
+
+// ApplyAntiAffinity adds the supplied anti-affinity rules to the pod template
+// of any Deployments, StatefulSets, DaemonSets, or Jobs in the supplied
+// objects. Each rule selects the pods of the supplied workload by
+// workload.LabelKey, or the label key configured by WithLabelKey. A rule
+// replaces any existing term of the same kind with the same label selector and
+// topology key, so applying the same rules again does not duplicate them.
+// Other existing affinity is preserved.
+func ApplyAntiAffinity(w resource.Workload, rules []AntiAffinity, objs []resource.Object, o ...Option) ([]resource.Object, error) {
+	opts := newOptions(o...)
+	var required []corev1.PodAffinityTerm
+	var preferred []corev1.WeightedPodAffinityTerm
+	for _, r := range rules {
+		if err := validateAntiAffinity(r); err != nil {
+			return nil, err
+		}
+		term := corev1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					opts.labelKey: string(w.GetUID()),
+				},
+			},
+			TopologyKey: r.TopologyKey,
+		}
+		if r.Required {
+			required = append(required, term)
+			continue
+		}
+		weight := r.Weight
+		if weight == 0 {
+			weight = defaultAntiAffinityWeight
+		}
+		preferred = append(preferred, corev1.WeightedPodAffinityTerm{Weight: weight, PodAffinityTerm: term})
+	}
+	if len(required) == 0 && len(preferred) == 0 {
+		return objs, nil
+	}
+
+	for _, o := range objs {
+		pt := workload.PodTemplate(o)
+		if pt == nil {
+			continue
+		}
+		ps := &pt.Spec
+		if ps.Affinity == nil {
+			ps.Affinity = &corev1.Affinity{}
+		}
+		if ps.Affinity.PodAntiAffinity == nil {
+			ps.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+		}
+		paa := ps.Affinity.PodAntiAffinity
+		for _, t := range required {
+			paa.RequiredDuringSchedulingIgnoredDuringExecution = withRequiredTerm(paa.RequiredDuringSchedulingIgnoredDuringExecution, t)
+		}
+		for _, t := range preferred {
+			paa.PreferredDuringSchedulingIgnoredDuringExecution = withPreferredTerm(paa.PreferredDuringSchedulingIgnoredDuringExecution, t)
+		}
+	}
+	return objs, nil
+}
+
+// withRequiredTerm returns the supplied terms with the supplied term added,
+// replacing any existing term with the same label selector and topology key.
+func withRequiredTerm(terms []corev1.PodAffinityTerm, t corev1.PodAffinityTerm) []corev1.PodAffinityTerm {
+	for i := range terms {
+		if sameTerm(terms[i], t) {
+			terms[i] = *t.DeepCopy()
+			return terms
+		}
+	}
+	return append(terms, *t.DeepCopy())
+}
+
+// withPreferredTerm returns the supplied terms with the supplied term added,
+// replacing any existing term with the same label selector and topology key.
+func withPreferredTerm(terms []corev1.WeightedPodAffinityTerm, t corev1.WeightedPodAffinityTerm) []corev1.WeightedPodAffinityTerm {
+	for i := range terms {
+		if sameTerm(terms[i].PodAffinityTerm, t.PodAffinityTerm) {
+			terms[i] = *t.DeepCopy()
+			return terms
+		}
+	}
+	return append(terms, *t.DeepCopy())
+}
+
+// sameTerm returns true if the supplied terms select the same pods across the
+// same topology.
+func sameTerm(a, b corev1.PodAffinityTerm) bool {
+	return a.TopologyKey == b.TopologyKey && reflect.DeepEqual(a.LabelSelector, b.LabelSelector)
+}
+
+// validateAntiAffinity returns an error if the supplied rule has no topology
+// key, or an invalid weight.
+func validateAntiAffinity(r AntiAffinity) error {
+	if r.TopologyKey == "" {
+		return errors.New(errEmptyAntiAffinityTopologyKey)
+	}
+	if r.Required {
+		if r.Weight != 0 {
+			return errors.Errorf(errFmtRequiredWithWeight, r.TopologyKey)
+		}
+		return nil
+	}
+	if r.Weight < 0 || r.Weight > 100 {
+		return errors.Errorf(errFmtInvalidAntiAffinityWeight, r.TopologyKey, r.Weight)
+	}
+	return nil
+}
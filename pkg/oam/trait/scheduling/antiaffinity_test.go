@@ -0,0 +1,291 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/pkg/oam/workload"
+)
+
+func TestApplyAntiAffinity(t *testing.T) {
+	uid := "a-very-unique-identifier"
+	w := &fake.Workload{ObjectMeta: metav1.ObjectMeta{Name: "cool-workload", UID: types.UID(uid)}}
+	sel := &metav1.LabelSelector{MatchLabels: map[string]string{workload.LabelKey: uid}}
+	customKey := "example.org/workload"
+
+	nodeAffinity := &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "cool", Operator: corev1.NodeSelectorOpExists}},
+			}},
+		},
+	}
+
+	type args struct {
+		rules []AntiAffinity
+		objs  []resource.Object
+		o     []Option
+	}
+
+	type want struct {
+		objs []resource.Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrorEmptyTopologyKey": {
+			reason: "A rule without a topology key should return an error.",
+			args: args{
+				rules: []AntiAffinity{{Required: true}},
+				objs:  []resource.Object{deployment()},
+			},
+			want: want{err: errors.New(errEmptyAntiAffinityTopologyKey)},
+		},
+		"ErrorInvalidWeight": {
+			reason: "A preferred rule with a weight greater than 100 should return an error.",
+			args: args{
+				rules: []AntiAffinity{{TopologyKey: "kubernetes.io/hostname", Weight: 101}},
+				objs:  []resource.Object{deployment()},
+			},
+			want: want{err: errors.Errorf(errFmtInvalidAntiAffinityWeight, "kubernetes.io/hostname", 101)},
+		},
+		"ErrorRequiredWithWeight": {
+			reason: "A required rule with a weight should return an error.",
+			args: args{
+				rules: []AntiAffinity{{TopologyKey: "kubernetes.io/hostname", Required: true, Weight: 50}},
+				objs:  []resource.Object{deployment()},
+			},
+			want: want{err: errors.Errorf(errFmtRequiredWithWeight, "kubernetes.io/hostname")},
+		},
+		"SuccessfulPreferred": {
+			reason: "A preferred rule should select the workload's pods and default to a weight of 100. Objects without a pod template should be unchanged.",
+			args: args{
+				rules: []AntiAffinity{{TopologyKey: "topology.kubernetes.io/zone"}},
+				objs:  []resource.Object{deployment(), &corev1.Service{}},
+			},
+			want: want{objs: []resource.Object{
+				deployment(dmWithAffinity(&corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+						Weight:          defaultAntiAffinityWeight,
+						PodAffinityTerm: corev1.PodAffinityTerm{LabelSelector: sel, TopologyKey: "topology.kubernetes.io/zone"},
+					}},
+				}})),
+				&corev1.Service{},
+			}},
+		},
+		"SuccessfulRequired": {
+			reason: "A required rule should select the workload's pods, preserving existing affinity.",
+			args: args{
+				rules: []AntiAffinity{
+					{TopologyKey: "kubernetes.io/hostname", Required: true},
+					{TopologyKey: "topology.kubernetes.io/zone", Weight: 10},
+				},
+				objs: []resource.Object{deployment(dmWithAffinity(&corev1.Affinity{NodeAffinity: nodeAffinity}))},
+			},
+			want: want{objs: []resource.Object{
+				deployment(dmWithAffinity(&corev1.Affinity{
+					NodeAffinity: nodeAffinity,
+					PodAntiAffinity: &corev1.PodAntiAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+							{LabelSelector: sel, TopologyKey: "kubernetes.io/hostname"},
+						},
+						PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+							Weight:          10,
+							PodAffinityTerm: corev1.PodAffinityTerm{LabelSelector: sel, TopologyKey: "topology.kubernetes.io/zone"},
+						}},
+					},
+				})),
+			}},
+		},
+		"SuccessfulReplaceExisting": {
+			reason: "A rule should replace an existing term with the same label selector and topology key, rather than duplicate it.",
+			args: args{
+				rules: []AntiAffinity{{TopologyKey: "topology.kubernetes.io/zone", Weight: 10}},
+				objs: []resource.Object{deployment(dmWithAffinity(&corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+						{Weight: 50, PodAffinityTerm: corev1.PodAffinityTerm{LabelSelector: sel, TopologyKey: "topology.kubernetes.io/zone"}},
+						{Weight: 50, PodAffinityTerm: corev1.PodAffinityTerm{LabelSelector: sel, TopologyKey: "kubernetes.io/hostname"}},
+					},
+				}}))},
+			},
+			want: want{objs: []resource.Object{
+				deployment(dmWithAffinity(&corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+						{Weight: 10, PodAffinityTerm: corev1.PodAffinityTerm{LabelSelector: sel, TopologyKey: "topology.kubernetes.io/zone"}},
+						{Weight: 50, PodAffinityTerm: corev1.PodAffinityTerm{LabelSelector: sel, TopologyKey: "kubernetes.io/hostname"}},
+					},
+				}})),
+			}},
+		},
+		"SuccessfulOtherKinds": {
+			reason: "Rules should be added to the pod template of StatefulSets, DaemonSets, and Jobs.",
+			args: args{
+				rules: []AntiAffinity{{TopologyKey: "kubernetes.io/hostname", Required: true}},
+				objs:  []resource.Object{&appsv1.StatefulSet{}, &appsv1.DaemonSet{}, &batchv1.Job{}},
+			},
+			want: want{objs: []resource.Object{
+				&appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Affinity: &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{LabelSelector: sel, TopologyKey: "kubernetes.io/hostname"}},
+				}}}}}},
+				&appsv1.DaemonSet{Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Affinity: &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{LabelSelector: sel, TopologyKey: "kubernetes.io/hostname"}},
+				}}}}}},
+				&batchv1.Job{Spec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Affinity: &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{LabelSelector: sel, TopologyKey: "kubernetes.io/hostname"}},
+				}}}}}},
+			}},
+		},
+		"SuccessfulCustomLabelKey": {
+			reason: "A rule should select the workload's pods by the configured label key.",
+			args: args{
+				rules: []AntiAffinity{{TopologyKey: "kubernetes.io/hostname", Required: true}},
+				objs:  []resource.Object{deployment()},
+				o:     []Option{WithLabelKey(customKey)},
+			},
+			want: want{objs: []resource.Object{
+				deployment(dmWithAffinity(&corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+						LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{customKey: uid}},
+						TopologyKey:   "kubernetes.io/hostname",
+					}},
+				}})),
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			objs, err := ApplyAntiAffinity(w, tc.args.rules, tc.args.objs, tc.args.o...)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nApplyAntiAffinity(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, objs); diff != "" {
+				t.Errorf("\nReason: %s\nApplyAntiAffinity(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyAntiAffinityTwice(t *testing.T) {
+	uid := "a-very-unique-identifier"
+	w := &fake.Workload{ObjectMeta: metav1.ObjectMeta{Name: "cool-workload", UID: types.UID(uid)}}
+	sel := &metav1.LabelSelector{MatchLabels: map[string]string{workload.LabelKey: uid}}
+	rules := []AntiAffinity{
+		{TopologyKey: "kubernetes.io/hostname", Required: true},
+		{TopologyKey: "topology.kubernetes.io/zone"},
+	}
+
+	objs, err := ApplyAntiAffinity(w, rules, []resource.Object{deployment()})
+	if err != nil {
+		t.Fatalf("ApplyAntiAffinity(...): %s", err)
+	}
+	objs, err = ApplyAntiAffinity(w, rules, objs)
+	if err != nil {
+		t.Fatalf("ApplyAntiAffinity(...): %s", err)
+	}
+
+	want := []resource.Object{deployment(dmWithAffinity(&corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{LabelSelector: sel, TopologyKey: "kubernetes.io/hostname"}},
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+			Weight:          defaultAntiAffinityWeight,
+			PodAffinityTerm: corev1.PodAffinityTerm{LabelSelector: sel, TopologyKey: "topology.kubernetes.io/zone"},
+		}},
+	}}))}
+	if diff := cmp.Diff(want, objs); diff != "" {
+		t.Errorf("ApplyAntiAffinity(...): applying the same rules twice should not duplicate them: -want, +got:\n%s", diff)
+	}
+}
+
+func TestApplyPlacementAndAntiAffinity(t *testing.T) {
+	uid := "a-very-unique-identifier"
+	w := &fake.Workload{ObjectMeta: metav1.ObjectMeta{Name: "cool-workload", UID: types.UID(uid)}}
+
+	p := Placement{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "cool", Operator: corev1.NodeSelectorOpExists}},
+			}},
+		},
+	}}
+	rules := []AntiAffinity{{TopologyKey: "kubernetes.io/hostname", Required: true}}
+
+	want := []resource.Object{deployment(dmWithAffinity(&corev1.Affinity{
+		NodeAffinity: p.NodeAffinity,
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{workload.LabelKey: uid}},
+				TopologyKey:   "kubernetes.io/hostname",
+			}},
+		},
+	}))}
+
+	cases := map[string]struct {
+		reason string
+		apply  func([]resource.Object) ([]resource.Object, error)
+	}{
+		"PlacementFirst": {
+			reason: "Anti-affinity applied after a placement should preserve its node affinity.",
+			apply: func(objs []resource.Object) ([]resource.Object, error) {
+				objs, err := ApplyPlacement(p, objs)
+				if err != nil {
+					return nil, err
+				}
+				return ApplyAntiAffinity(w, rules, objs)
+			},
+		},
+		"AntiAffinityFirst": {
+			reason: "A placement applied after anti-affinity should preserve its pod anti-affinity.",
+			apply: func(objs []resource.Object) ([]resource.Object, error) {
+				objs, err := ApplyAntiAffinity(w, rules, objs)
+				if err != nil {
+					return nil, err
+				}
+				return ApplyPlacement(p, objs)
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.apply([]resource.Object{deployment()})
+			if err != nil {
+				t.Fatalf("\nReason: %s\napply(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("\nReason: %s\napply(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
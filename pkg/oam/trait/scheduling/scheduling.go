@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/crossplane/pkg/oam/workload"
+)
+
+const (
+	errFmtConflictingNodeSelector = "pod template of %q: node selector %q is %q, cannot set it to %q"
+	errFmtEqualWithoutValue       = "toleration for key %q uses operator Equal but has no value"
+	errFmtUnknownOperator         = "toleration for key %q uses unknown operator %q"
+)
+
+// An Option configures how scheduling constraints are applied.
+type Option func(*options)
+
+type options struct {
+	labelKey string
+}
+
+// WithLabelKey configures the label used by topology spread constraints and
+// anti-affinity rules to select the workload's pods. workload.LabelKey is used
+// by default.
+func WithLabelKey(k string) Option {
+	return func(o *options) {
+		o.labelKey = k
+	}
+}
+
+// newOptions returns the default options, modified by the supplied Options.
+func newOptions(o ...Option) options {
+	opts := options{labelKey: workload.LabelKey}
+	for _, fn := range o {
+		fn(&opts)
+	}
+	return opts
+}
+
+// A Placement constrains the nodes a workload's pods may be scheduled to.
+type Placement struct {
+	// NodeSelector labels that a node must have for pods to be scheduled to
+	// it.
+	NodeSelector map[string]string
+
+	// NodeAffinity scheduling rules. Existing node affinity is replaced if
+	// specified. Pod affinity and anti-affinity are left untouched.
+	NodeAffinity *corev1.NodeAffinity
+}
+
+// ApplyPlacement constrains the pods of any Deployments, StatefulSets,
+// DaemonSets, or Jobs in the supplied objects to the supplied Placement. Node
+// selectors are merged with those already set on each pod template; it is an
+// error to change the value of an existing node selector. Node affinity is set without affecting
+// any pod affinity or anti-affinity, such as that added by ApplyAntiAffinity.
+func ApplyPlacement(p Placement, objs []resource.Object) ([]resource.Object, error) {
+	for _, o := range objs {
+		t := workload.PodTemplate(o)
+		if t == nil {
+			continue
+		}
+		ps := &t.Spec
+
+		for k, v := range p.NodeSelector {
+			if existing, ok := ps.NodeSelector[k]; ok && existing != v {
+				return nil, errors.Errorf(errFmtConflictingNodeSelector, o.GetName(), k, existing, v)
+			}
+		}
+		for k, v := range p.NodeSelector {
+			if ps.NodeSelector == nil {
+				ps.NodeSelector = map[string]string{}
+			}
+			ps.NodeSelector[k] = v
+		}
+
+		if p.NodeAffinity != nil {
+			if ps.Affinity == nil {
+				ps.Affinity = &corev1.Affinity{}
+			}
+			ps.Affinity.NodeAffinity = p.NodeAffinity.DeepCopy()
+		}
+	}
+
+	return objs, nil
+}
+
+// ApplyTolerations appends the supplied tolerations to the pod template of any
+// Deployments, StatefulSets, DaemonSets, or Jobs in the supplied objects. Tolerations identical to one already
+// present are not appended. A toleration with an empty operator is treated as
+// using the Equal operator.
+func ApplyTolerations(tolerations []corev1.Toleration, objs []resource.Object) ([]resource.Object, error) {
+	for _, t := range tolerations {
+		if err := validateToleration(t); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, o := range objs {
+		pt := workload.PodTemplate(o)
+		if pt == nil {
+			continue
+		}
+		ps := &pt.Spec
+		for _, t := range tolerations {
+			if !hasToleration(ps.Tolerations, t) {
+				ps.Tolerations = append(ps.Tolerations, t)
+			}
+		}
+	}
+
+	return objs, nil
+}
+
+// validateToleration returns an error if the supplied toleration's operator is
+// unknown, or if it is an Equal toleration without a value.
+func validateToleration(t corev1.Toleration) error {
+	switch t.Operator {
+	case corev1.TolerationOpExists:
+		return nil
+	case corev1.TolerationOpEqual, "":
+		if t.Value == "" {
+			return errors.Errorf(errFmtEqualWithoutValue, t.Key)
+		}
+		return nil
+	default:
+		return errors.Errorf(errFmtUnknownOperator, t.Key, t.Operator)
+	}
+}
+
+// hasToleration returns true if the supplied toleration is identical to any of
+// the existing tolerations.
+func hasToleration(existing []corev1.Toleration, t corev1.Toleration) bool {
+	for _, e := range existing {
+		if reflect.DeepEqual(e, t) {
+			return true
+		}
+	}
+	return false
+}
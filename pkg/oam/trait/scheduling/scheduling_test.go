@@ -0,0 +1,277 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var deploymentName = "cool-deployment"
+
+type deploymentModifier func(*appsv1.Deployment)
+
+func dmWithNodeSelector(ns map[string]string) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.NodeSelector = ns
+	}
+}
+
+func dmWithAffinity(a *corev1.Affinity) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.Affinity = a
+	}
+}
+
+func dmWithTolerations(t ...corev1.Toleration) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.Tolerations = t
+	}
+}
+
+func deployment(mod ...deploymentModifier) *appsv1.Deployment {
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: deploymentName},
+	}
+	for _, m := range mod {
+		m(d)
+	}
+	return d
+}
+
+func TestApplyPlacement(t *testing.T) {
+	nodeAffinity := &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      "cloud.google.com/gke-spot",
+					Operator: corev1.NodeSelectorOpExists,
+				}},
+			}},
+		},
+	}
+	podAntiAffinity := &corev1.PodAntiAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+			Weight:          100,
+			PodAffinityTerm: corev1.PodAffinityTerm{TopologyKey: "kubernetes.io/hostname"},
+		}},
+	}
+
+	type args struct {
+		p    Placement
+		objs []resource.Object
+	}
+
+	type want struct {
+		objs []resource.Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrorConflictingNodeSelector": {
+			reason: "Changing the value of an existing node selector should return an error.",
+			args: args{
+				p:    Placement{NodeSelector: map[string]string{"kubernetes.io/arch": "arm64"}},
+				objs: []resource.Object{deployment(dmWithNodeSelector(map[string]string{"kubernetes.io/arch": "amd64"}))},
+			},
+			want: want{err: errors.Errorf(errFmtConflictingNodeSelector, deploymentName, "kubernetes.io/arch", "amd64", "arm64")},
+		},
+		"SuccessfulSet": {
+			reason: "Node selectors should be set on a pod template that has none.",
+			args: args{
+				p:    Placement{NodeSelector: map[string]string{"accelerator": "gpu"}},
+				objs: []resource.Object{deployment()},
+			},
+			want: want{objs: []resource.Object{deployment(dmWithNodeSelector(map[string]string{"accelerator": "gpu"}))}},
+		},
+		"SuccessfulMerge": {
+			reason: "Node selectors should be merged with existing node selectors, including identical ones.",
+			args: args{
+				p: Placement{NodeSelector: map[string]string{
+					"accelerator":        "gpu",
+					"kubernetes.io/arch": "amd64",
+				}},
+				objs: []resource.Object{deployment(dmWithNodeSelector(map[string]string{"kubernetes.io/arch": "amd64"}))},
+			},
+			want: want{objs: []resource.Object{deployment(dmWithNodeSelector(map[string]string{
+				"accelerator":        "gpu",
+				"kubernetes.io/arch": "amd64",
+			}))}},
+		},
+		"SuccessfulAffinity": {
+			reason: "Node affinity should be set on the pod template, and other objects left untouched.",
+			args: args{
+				p:    Placement{NodeAffinity: nodeAffinity},
+				objs: []resource.Object{deployment(), &corev1.Service{}},
+			},
+			want: want{objs: []resource.Object{deployment(dmWithAffinity(&corev1.Affinity{NodeAffinity: nodeAffinity})), &corev1.Service{}}},
+		},
+		"SuccessfulOtherKinds": {
+			reason: "Placement should be applied to the pod template of StatefulSets, DaemonSets, and Jobs.",
+			args: args{
+				p: Placement{NodeSelector: map[string]string{"accelerator": "gpu"}},
+				objs: []resource.Object{
+					&appsv1.StatefulSet{},
+					&appsv1.DaemonSet{},
+					&batchv1.Job{},
+				},
+			},
+			want: want{objs: []resource.Object{
+				&appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{NodeSelector: map[string]string{"accelerator": "gpu"}}}}},
+				&appsv1.DaemonSet{Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{NodeSelector: map[string]string{"accelerator": "gpu"}}}}},
+				&batchv1.Job{Spec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{NodeSelector: map[string]string{"accelerator": "gpu"}}}}},
+			}},
+		},
+		"SuccessfulAffinityMerge": {
+			reason: "Node affinity should be set without affecting existing pod anti-affinity.",
+			args: args{
+				p:    Placement{NodeAffinity: nodeAffinity},
+				objs: []resource.Object{deployment(dmWithAffinity(&corev1.Affinity{PodAntiAffinity: podAntiAffinity}))},
+			},
+			want: want{objs: []resource.Object{deployment(dmWithAffinity(&corev1.Affinity{
+				NodeAffinity:    nodeAffinity,
+				PodAntiAffinity: podAntiAffinity,
+			}))}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			objs, err := ApplyPlacement(tc.args.p, tc.args.objs)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nApplyPlacement(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, objs); diff != "" {
+				t.Errorf("\nReason: %s\nApplyPlacement(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyTolerations(t *testing.T) {
+	spot := corev1.Toleration{
+		Key:      "cloud.google.com/gke-spot",
+		Operator: corev1.TolerationOpExists,
+		Effect:   corev1.TaintEffectNoSchedule,
+	}
+	gpu := corev1.Toleration{
+		Key:      "nvidia.com/gpu",
+		Operator: corev1.TolerationOpEqual,
+		Value:    "present",
+		Effect:   corev1.TaintEffectNoSchedule,
+	}
+
+	type args struct {
+		t    []corev1.Toleration
+		objs []resource.Object
+	}
+
+	type want struct {
+		objs []resource.Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrorEqualWithoutValue": {
+			reason: "An Equal toleration without a value should return an error.",
+			args: args{
+				t:    []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual}},
+				objs: []resource.Object{deployment()},
+			},
+			want: want{err: errors.Errorf(errFmtEqualWithoutValue, "dedicated")},
+		},
+		"ErrorDefaultOperatorWithoutValue": {
+			reason: "A toleration with no operator is an Equal toleration, and should return an error without a value.",
+			args: args{
+				t:    []corev1.Toleration{{Key: "dedicated"}},
+				objs: []resource.Object{deployment()},
+			},
+			want: want{err: errors.Errorf(errFmtEqualWithoutValue, "dedicated")},
+		},
+		"ErrorUnknownOperator": {
+			reason: "A toleration with an unknown operator should return an error.",
+			args: args{
+				t:    []corev1.Toleration{{Key: "dedicated", Operator: "Near"}},
+				objs: []resource.Object{deployment()},
+			},
+			want: want{err: errors.Errorf(errFmtUnknownOperator, "dedicated", "Near")},
+		},
+		"SuccessfulAppend": {
+			reason: "Exists and Equal tolerations should be appended to existing tolerations.",
+			args: args{
+				t:    []corev1.Toleration{gpu},
+				objs: []resource.Object{deployment(dmWithTolerations(spot))},
+			},
+			want: want{objs: []resource.Object{deployment(dmWithTolerations(spot, gpu))}},
+		},
+		"SuccessfulDeduplicate": {
+			reason: "Tolerations identical to existing or earlier tolerations should not be appended.",
+			args: args{
+				t:    []corev1.Toleration{spot, gpu, gpu},
+				objs: []resource.Object{deployment(dmWithTolerations(spot)), &corev1.Service{}},
+			},
+			want: want{objs: []resource.Object{deployment(dmWithTolerations(spot, gpu)), &corev1.Service{}}},
+		},
+		"SuccessfulOtherKinds": {
+			reason: "Tolerations should be appended to the pod template of StatefulSets, DaemonSets, and Jobs.",
+			args: args{
+				t: []corev1.Toleration{spot},
+				objs: []resource.Object{
+					&appsv1.StatefulSet{},
+					&appsv1.DaemonSet{},
+					&batchv1.Job{},
+				},
+			},
+			want: want{objs: []resource.Object{
+				&appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Tolerations: []corev1.Toleration{spot}}}}},
+				&appsv1.DaemonSet{Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Tolerations: []corev1.Toleration{spot}}}}},
+				&batchv1.Job{Spec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Tolerations: []corev1.Toleration{spot}}}}},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			objs, err := ApplyTolerations(tc.args.t, tc.args.objs)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nApplyTolerations(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, objs); diff != "" {
+				t.Errorf("\nReason: %s\nApplyTolerations(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/crossplane/pkg/oam/workload"
+)
+
+const (
+	errFmtInvalidMaxSkew           = "topology spread constraint for key %q: max skew must be at least 1, got %d"
+	errEmptyTopologyKey            = "topology spread constraint must specify a topology key"
+	errFmtUnknownWhenUnsatisfiable = "topology spread constraint for key %q: unknown whenUnsatisfiable action %q"
+)
+
+// A SpreadConstraint controls how a workload's pods are spread across a
+// topology, such as zones or nodes.
+type SpreadConstraint struct {
+	// MaxSkew is the maximum permitted difference between the number of the
+	// workload's pods in any two topology domains. It must be at least 1.
+	MaxSkew int32
+
+	// TopologyKey is the node label that identifies a topology domain, for
+	// example topology.kubernetes.io/zone.
+	TopologyKey string
+
+	// WhenUnsatisfiable specifies how to schedule a pod that would violate the
+	// constraint. Pods are not scheduled if it is omitted.
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction
+}
+
+// ApplyTopologySpread adds the supplied spread constraints to the pod template
+// of any Deployments, StatefulSets, DaemonSets, or Jobs in the supplied
+// objects. Each constraint counts the pods of the supplied workload, selected
+// by workload.LabelKey or the label key configured by WithLabelKey. A
+// constraint replaces any existing constraint with the same topology key, so
+// applying the same constraints again does not duplicate them.
+func ApplyTopologySpread(w resource.Workload, constraints []SpreadConstraint, objs []resource.Object, o ...Option) ([]resource.Object, error) {
+	opts := newOptions(o...)
+	tscs := make([]corev1.TopologySpreadConstraint, 0, len(constraints))
+	for _, c := range constraints {
+		if err := validateSpreadConstraint(c); err != nil {
+			return nil, err
+		}
+		wu := c.WhenUnsatisfiable
+		if wu == "" {
+			wu = corev1.DoNotSchedule
+		}
+		tscs = append(tscs, corev1.TopologySpreadConstraint{
+			MaxSkew:           c.MaxSkew,
+			TopologyKey:       c.TopologyKey,
+			WhenUnsatisfiable: wu,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					opts.labelKey: string(w.GetUID()),
+				},
+			},
+		})
+	}
+
+	for _, o := range objs {
+		t := workload.PodTemplate(o)
+		if t == nil {
+			continue
+		}
+		ps := &t.Spec
+		for _, c := range tscs {
+			ps.TopologySpreadConstraints = withSpreadConstraint(ps.TopologySpreadConstraints, c)
+		}
+	}
+	return objs, nil
+}
+
+// withSpreadConstraint returns the supplied constraints with the supplied
+// constraint added, replacing any existing constraint with the same topology
+// key.
+func withSpreadConstraint(tscs []corev1.TopologySpreadConstraint, c corev1.TopologySpreadConstraint) []corev1.TopologySpreadConstraint {
+	for i := range tscs {
+		if tscs[i].TopologyKey == c.TopologyKey {
+			tscs[i] = *c.DeepCopy()
+			return tscs
+		}
+	}
+	return append(tscs, *c.DeepCopy())
+}
+
+// validateSpreadConstraint returns an error if the supplied constraint has no
+// topology key, a max skew less than 1, or an unknown whenUnsatisfiable
+// action.
+func validateSpreadConstraint(c SpreadConstraint) error {
+	if c.TopologyKey == "" {
+		return errors.New(errEmptyTopologyKey)
+	}
+	if c.MaxSkew < 1 {
+		return errors.Errorf(errFmtInvalidMaxSkew, c.TopologyKey, c.MaxSkew)
+	}
+	switch c.WhenUnsatisfiable {
+	case corev1.DoNotSchedule, corev1.ScheduleAnyway, "":
+		return nil
+	default:
+		return errors.Errorf(errFmtUnknownWhenUnsatisfiable, c.TopologyKey, c.WhenUnsatisfiable)
+	}
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/pkg/oam/workload"
+)
+
+func dmWithSpread(c ...corev1.TopologySpreadConstraint) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.TopologySpreadConstraints = c
+	}
+}
+
+func TestApplyTopologySpread(t *testing.T) {
+	uid := "a-very-unique-identifier"
+	w := &fake.Workload{ObjectMeta: metav1.ObjectMeta{Name: "cool-workload", UID: types.UID(uid)}}
+	sel := &metav1.LabelSelector{MatchLabels: map[string]string{workload.LabelKey: uid}}
+	customKey := "example.org/workload"
+
+	type args struct {
+		c    []SpreadConstraint
+		objs []resource.Object
+		o    []Option
+	}
+
+	type want struct {
+		objs []resource.Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrorEmptyTopologyKey": {
+			reason: "A constraint without a topology key should return an error.",
+			args: args{
+				c:    []SpreadConstraint{{MaxSkew: 1}},
+				objs: []resource.Object{deployment()},
+			},
+			want: want{err: errors.New(errEmptyTopologyKey)},
+		},
+		"ErrorInvalidMaxSkew": {
+			reason: "A constraint with a max skew less than 1 should return an error.",
+			args: args{
+				c:    []SpreadConstraint{{MaxSkew: 0, TopologyKey: "topology.kubernetes.io/zone"}},
+				objs: []resource.Object{deployment()},
+			},
+			want: want{err: errors.Errorf(errFmtInvalidMaxSkew, "topology.kubernetes.io/zone", 0)},
+		},
+		"ErrorUnknownWhenUnsatisfiable": {
+			reason: "A constraint with an unknown whenUnsatisfiable action should return an error.",
+			args: args{
+				c:    []SpreadConstraint{{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: "Panic"}},
+				objs: []resource.Object{deployment()},
+			},
+			want: want{err: errors.Errorf(errFmtUnknownWhenUnsatisfiable, "topology.kubernetes.io/zone", "Panic")},
+		},
+		"SuccessfulSingleConstraint": {
+			reason: "A constraint should select the workload's pods and default to DoNotSchedule. Objects without a pod template should be unchanged.",
+			args: args{
+				c:    []SpreadConstraint{{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone"}},
+				objs: []resource.Object{deployment(), &corev1.Service{}},
+			},
+			want: want{objs: []resource.Object{
+				deployment(dmWithSpread(corev1.TopologySpreadConstraint{
+					MaxSkew:           1,
+					TopologyKey:       "topology.kubernetes.io/zone",
+					WhenUnsatisfiable: corev1.DoNotSchedule,
+					LabelSelector:     sel,
+				})),
+				&corev1.Service{},
+			}},
+		},
+		"SuccessfulReplaceExisting": {
+			reason: "A constraint should replace an existing constraint with the same topology key, rather than duplicate it.",
+			args: args{
+				c: []SpreadConstraint{{MaxSkew: 2, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.ScheduleAnyway}},
+				objs: []resource.Object{deployment(dmWithSpread(
+					corev1.TopologySpreadConstraint{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule, LabelSelector: sel},
+					corev1.TopologySpreadConstraint{MaxSkew: 1, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: corev1.DoNotSchedule, LabelSelector: sel},
+				))},
+			},
+			want: want{objs: []resource.Object{
+				deployment(dmWithSpread(
+					corev1.TopologySpreadConstraint{MaxSkew: 2, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.ScheduleAnyway, LabelSelector: sel},
+					corev1.TopologySpreadConstraint{MaxSkew: 1, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: corev1.DoNotSchedule, LabelSelector: sel},
+				)),
+			}},
+		},
+		"SuccessfulOtherKinds": {
+			reason: "Constraints should be added to the pod template of StatefulSets, DaemonSets, and Jobs.",
+			args: args{
+				c:    []SpreadConstraint{{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone"}},
+				objs: []resource.Object{&appsv1.StatefulSet{}, &appsv1.DaemonSet{}, &batchv1.Job{}},
+			},
+			want: want{objs: []resource.Object{
+				&appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+					{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule, LabelSelector: sel},
+				}}}}},
+				&appsv1.DaemonSet{Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+					{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule, LabelSelector: sel},
+				}}}}},
+				&batchv1.Job{Spec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+					{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule, LabelSelector: sel},
+				}}}}},
+			}},
+		},
+		"SuccessfulCustomLabelKey": {
+			reason: "A constraint should select the workload's pods by the configured label key.",
+			args: args{
+				c:    []SpreadConstraint{{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone"}},
+				objs: []resource.Object{deployment()},
+				o:    []Option{WithLabelKey(customKey)},
+			},
+			want: want{objs: []resource.Object{
+				deployment(dmWithSpread(corev1.TopologySpreadConstraint{
+					MaxSkew:           1,
+					TopologyKey:       "topology.kubernetes.io/zone",
+					WhenUnsatisfiable: corev1.DoNotSchedule,
+					LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{customKey: uid}},
+				})),
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			objs, err := ApplyTopologySpread(w, tc.args.c, tc.args.objs, tc.args.o...)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nApplyTopologySpread(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, objs); diff != "" {
+				t.Errorf("\nReason: %s\nApplyTopologySpread(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyTopologySpreadTwice(t *testing.T) {
+	uid := "a-very-unique-identifier"
+	w := &fake.Workload{ObjectMeta: metav1.ObjectMeta{Name: "cool-workload", UID: types.UID(uid)}}
+	sel := &metav1.LabelSelector{MatchLabels: map[string]string{workload.LabelKey: uid}}
+	c := []SpreadConstraint{
+		{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone"},
+		{MaxSkew: 1, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: corev1.ScheduleAnyway},
+	}
+
+	objs, err := ApplyTopologySpread(w, c, []resource.Object{deployment()})
+	if err != nil {
+		t.Fatalf("ApplyTopologySpread(...): %s", err)
+	}
+	objs, err = ApplyTopologySpread(w, c, objs)
+	if err != nil {
+		t.Fatalf("ApplyTopologySpread(...): %s", err)
+	}
+
+	want := []resource.Object{deployment(dmWithSpread(
+		corev1.TopologySpreadConstraint{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule, LabelSelector: sel},
+		corev1.TopologySpreadConstraint{MaxSkew: 1, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: corev1.ScheduleAnyway, LabelSelector: sel},
+	))}
+	if diff := cmp.Diff(want, objs); diff != "" {
+		t.Errorf("ApplyTopologySpread(...): applying the same constraints twice should not duplicate them: -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// DefaultPodAnnotationPrefixes are the prefixes of workload annotations that
+// PodAnnotator propagates to pod templates.
+var DefaultPodAnnotationPrefixes = []string{
+	"pod.oam.dev/",
+	"sidecar.istio.io/",
+}
+
+// PodAnnotator copies each workload annotation with one of the
+// DefaultPodAnnotationPrefixes to the pod template of each translated object
+// that manages pods, for example to request sidecar injection by a service
+// mesh. PodAnnotator should be run before KubeAppWrapper in order for the
+// wrapped objects to be annotated.
+func PodAnnotator(ctx context.Context, w resource.Workload, objs []resource.Object) ([]resource.Object, error) {
+	return NewPodAnnotator()(ctx, w, objs)
+}
+
+// NewPodAnnotator returns a TranslationWrapper that behaves like PodAnnotator,
+// but that copies workload annotations with any of the supplied prefixes.
+// DefaultPodAnnotationPrefixes are used if no prefixes are supplied. Copied
+// annotations replace any pod template annotation of the same key.
+func NewPodAnnotator(prefixes ...string) workload.TranslationWrapper {
+	if len(prefixes) == 0 {
+		prefixes = DefaultPodAnnotationPrefixes
+	}
+	return func(ctx context.Context, w resource.Workload, objs []resource.Object) ([]resource.Object, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		a := prefixedAnnotations(w, prefixes)
+		if len(a) == 0 {
+			return objs, nil
+		}
+
+		for _, o := range objs {
+			t := PodTemplate(o)
+			if t == nil {
+				continue
+			}
+			pa := t.GetAnnotations()
+			if pa == nil {
+				pa = make(map[string]string, len(a))
+			}
+			for k, v := range a {
+				pa[k] = v
+			}
+			t.SetAnnotations(pa)
+		}
+		return objs, nil
+	}
+}
+
+// prefixedAnnotations returns the annotations of the supplied object that
+// have any of the supplied prefixes.
+func prefixedAnnotations(o resource.Object, prefixes []string) map[string]string {
+	var a map[string]string
+	for k, v := range o.GetAnnotations() {
+		for _, p := range prefixes {
+			if !strings.HasPrefix(k, p) {
+				continue
+			}
+			if a == nil {
+				a = map[string]string{}
+			}
+			a[k] = v
+			break
+		}
+	}
+	return a
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+)
+
+var _ workload.TranslationWrapper = PodAnnotator
+
+func TestPodAnnotator(t *testing.T) {
+	withPodAnnotations := func(a map[string]string) deploymentModifier {
+		return func(d *appsv1.Deployment) {
+			d.Spec.Template.SetAnnotations(a)
+		}
+	}
+
+	type args struct {
+		prefixes    []string
+		annotations map[string]string
+		o           resource.Object
+	}
+
+	type want struct {
+		annotations    map[string]string
+		podAnnotations map[string]string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"DefaultPrefixes": {
+			reason: "Workload annotations with a default prefix should be copied to the pod template, but not the Deployment.",
+			args: args{
+				annotations: map[string]string{
+					"sidecar.istio.io/inject": "true",
+					"pod.oam.dev/cool":        "very",
+					"unrelated":               "nope",
+				},
+				o: deployment(),
+			},
+			want: want{
+				podAnnotations: map[string]string{
+					"sidecar.istio.io/inject": "true",
+					"pod.oam.dev/cool":        "very",
+				},
+			},
+		},
+		"CustomPrefixes": {
+			reason: "Only workload annotations with a configured prefix should be copied to the pod template.",
+			args: args{
+				prefixes: []string{"linkerd.io/"},
+				annotations: map[string]string{
+					"linkerd.io/inject":       "enabled",
+					"sidecar.istio.io/inject": "true",
+				},
+				o: deployment(),
+			},
+			want: want{
+				podAnnotations: map[string]string{"linkerd.io/inject": "enabled"},
+			},
+		},
+		"ExistingAnnotations": {
+			reason: "Copied annotations should be merged with existing pod template annotations, replacing those of the same key.",
+			args: args{
+				annotations: map[string]string{"sidecar.istio.io/inject": "true"},
+				o: deployment(withPodAnnotations(map[string]string{
+					"sidecar.istio.io/inject": "false",
+					"cool":                    "very",
+				})),
+			},
+			want: want{
+				podAnnotations: map[string]string{
+					"sidecar.istio.io/inject": "true",
+					"cool":                    "very",
+				},
+			},
+		},
+		"NoMatchingAnnotations": {
+			reason: "The pod template should be unchanged when no workload annotation matches.",
+			args: args{
+				annotations: map[string]string{"unrelated": "nope"},
+				o:           deployment(),
+			},
+			want: want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := &fake.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        workloadName,
+					Namespace:   workloadNamespace,
+					UID:         types.UID(workloadUID),
+					Annotations: tc.args.annotations,
+				},
+			}
+
+			objs, err := NewPodAnnotator(tc.args.prefixes...)(context.Background(), w, []resource.Object{tc.args.o})
+			if err != nil {
+				t.Fatalf("NewPodAnnotator(...): %s", err)
+			}
+			d := objs[0].(*appsv1.Deployment)
+
+			if diff := cmp.Diff(tc.want.annotations, d.GetAnnotations()); diff != "" {
+				t.Errorf("\nReason: %s\nNewPodAnnotator(...): -want Deployment annotations, +got Deployment annotations:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.podAnnotations, d.Spec.Template.GetAnnotations()); diff != "" {
+				t.Errorf("\nReason: %s\nNewPodAnnotator(...): -want pod template annotations, +got pod template annotations:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errFmtTranslateWorkload = "cannot translate workload %s/%s"
+)
+
+// TranslateAll translates each of the supplied workloads using the supplied
+// TranslateFn, runs the objects produced for each workload through the
+// supplied wrappers in order, and returns the objects produced for all
+// workloads in the order the workloads were supplied. Objects that were not
+// placed in a namespace are placed in the namespace of the workload they were
+// produced for, unless they are of a well known cluster scoped kind such as
+// ClusterRole or PriorityClass. TranslateAll returns the first error
+// encountered, identifying the workload that could not be translated.
+func TranslateAll(ctx context.Context, ws []resource.Workload, fn workload.TranslateFn, wrappers ...workload.TranslationWrapper) ([]resource.Object, error) {
+	t := NewTranslator(WithWrappers(wrappers...))
+
+	var all []resource.Object
+	for _, w := range ws {
+		objs, err := fn(ctx, w)
+		if err == nil {
+			objs, err = t.Wrap(ctx, w, objs)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtTranslateWorkload, w.GetNamespace(), w.GetName())
+		}
+		for _, o := range objs {
+			cs, err := clusterScoped(nil, o)
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtTranslateWorkload, w.GetNamespace(), w.GetName())
+			}
+			if o.GetNamespace() == "" && !cs {
+				o.SetNamespace(w.GetNamespace())
+			}
+		}
+		all = append(all, objs...)
+	}
+	return all, nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+// deploymentFor is a TranslateFn that translates a workload into a
+// Deployment named for and selecting the pods of the workload.
+func deploymentFor(_ context.Context, w resource.Workload) ([]resource.Object, error) {
+	d := deployment(dmWithContainerPorts(3000))
+	d.SetName(w.GetName())
+	d.Spec.Selector.MatchLabels[LabelKey] = string(w.GetUID())
+	d.Spec.Template.Labels[LabelKey] = string(w.GetUID())
+	return []resource.Object{d}, nil
+}
+
+func TestTranslateAll(t *testing.T) {
+	a := &fake.Workload{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns-a", UID: types.UID("uid-a")}}
+	b := &fake.Workload{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "ns-b", UID: types.UID("uid-b")}}
+
+	type args struct {
+		ws       []resource.Workload
+		fn       workload.TranslateFn
+		wrappers []workload.TranslationWrapper
+	}
+
+	// A source identifies the workload an object was produced for.
+	type source struct {
+		Kind      string
+		Name      string
+		Namespace string
+		Label     string
+	}
+
+	type want struct {
+		sources []source
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"TwoWorkloads": {
+			reason: "The objects produced for each workload should be namespaced and labelled for that workload only.",
+			args: args{
+				ws:       []resource.Workload{a, b},
+				fn:       deploymentFor,
+				wrappers: []workload.TranslationWrapper{ServiceInjector},
+			},
+			want: want{sources: []source{
+				{Kind: deploymentKind, Name: "a", Namespace: "ns-a", Label: "uid-a"},
+				{Kind: serviceKind, Name: "a" + ServiceNameSuffix, Namespace: "ns-a", Label: "uid-a"},
+				{Kind: deploymentKind, Name: "b", Namespace: "ns-b", Label: "uid-b"},
+				{Kind: serviceKind, Name: "b" + ServiceNameSuffix, Namespace: "ns-b", Label: "uid-b"},
+			}},
+		},
+		"ClusterScoped": {
+			reason: "Objects of well known cluster scoped kinds should not be placed in the workload's namespace.",
+			args: args{
+				ws: []resource.Workload{a},
+				fn: func(ctx context.Context, w resource.Workload) ([]resource.Object, error) {
+					cr := &rbacv1.ClusterRole{
+						TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+						ObjectMeta: metav1.ObjectMeta{Name: w.GetName()},
+					}
+					objs, err := deploymentFor(ctx, w)
+					return append(objs, cr), err
+				},
+			},
+			want: want{sources: []source{
+				{Kind: deploymentKind, Name: "a", Namespace: "ns-a", Label: "uid-a"},
+				{Kind: "ClusterRole", Name: "a"},
+			}},
+		},
+		"ErrorTranslate": {
+			reason: "An error translating a workload should be returned, identifying the workload.",
+			args: args{
+				ws: []resource.Workload{a, b},
+				fn: func(ctx context.Context, w resource.Workload) ([]resource.Object, error) {
+					if w.GetName() == "b" {
+						return nil, errBoom
+					}
+					return deploymentFor(ctx, w)
+				},
+			},
+			want: want{err: errors.Wrapf(errBoom, errFmtTranslateWorkload, "ns-b", "b")},
+		},
+		"ErrorWrapper": {
+			reason: "An error wrapping a workload's objects should be returned, identifying the workload.",
+			args: args{
+				ws:       []resource.Workload{a, b},
+				fn:       deploymentFor,
+				wrappers: []workload.TranslationWrapper{errorWrapper},
+			},
+			want: want{err: errors.Wrapf(errBoom, errFmtTranslateWorkload, "ns-a", "a")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			objs, err := TranslateAll(context.Background(), tc.args.ws, tc.args.fn, tc.args.wrappers...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nTranslateAll(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			var got []source
+			for _, o := range objs {
+				s := source{Kind: o.GetObjectKind().GroupVersionKind().Kind, Name: o.GetName(), Namespace: o.GetNamespace()}
+				switch o := o.(type) {
+				case *appsv1.Deployment:
+					s.Label = o.Spec.Template.Labels[LabelKey]
+				case *corev1.Service:
+					s.Label = o.Spec.Selector[LabelKey]
+				}
+				got = append(got, s)
+			}
+			if diff := cmp.Diff(tc.want.sources, got); diff != "" {
+				t.Errorf("\nReason: %s\nTranslateAll(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errFmtConflicts = "translated Services conflict: %s"
+)
+
+// A ConflictKind identifies what two or more translated Services claim.
+type ConflictKind string
+
+// Kinds of conflict.
+const (
+	// ConflictKindServiceName indicates that Services share a namespace and
+	// name.
+	ConflictKindServiceName ConflictKind = "ServiceName"
+
+	// ConflictKindNodePort indicates that Services claim the same node port.
+	// Node ports are allocated cluster wide, so Services in different
+	// namespaces may conflict.
+	ConflictKindNodePort ConflictKind = "NodePort"
+)
+
+// A Conflict between two or more translated Services.
+type Conflict struct {
+	// Kind of conflict.
+	Kind ConflictKind
+
+	// Namespace and Name of the conflicting Services. Only set for
+	// ConflictKindServiceName conflicts.
+	Namespace string
+	Name      string
+
+	// NodePort claimed by the conflicting Services. Only set for
+	// ConflictKindNodePort conflicts.
+	NodePort int32
+
+	// Services that conflict, as <namespace>/<name>, in the order they were
+	// supplied.
+	Services []string
+}
+
+// String returns a human readable description of the conflict.
+func (c Conflict) String() string {
+	if c.Kind == ConflictKindNodePort {
+		return fmt.Sprintf("node port %d is claimed by %s", c.NodePort, strings.Join(c.Services, ", "))
+	}
+	return fmt.Sprintf("Service %s/%s is produced %d times", c.Namespace, c.Name, len(c.Services))
+}
+
+// A ConflictReport lists the conflicts found by DetectServiceConflicts.
+type ConflictReport struct {
+	Conflicts []Conflict
+}
+
+// Err returns an error describing every conflict in the report, or nil if
+// the report contains no conflicts.
+func (r ConflictReport) Err() error {
+	if len(r.Conflicts) == 0 {
+		return nil
+	}
+	desc := make([]string, len(r.Conflicts))
+	for i, c := range r.Conflicts {
+		desc[i] = c.String()
+	}
+	return errors.Errorf(errFmtConflicts, strings.Join(desc, "; "))
+}
+
+// DetectServiceConflicts reports Services in the supplied batch of translated
+// objects that share a namespace and name, or that claim the same node port.
+// It allows a batch that would fail to apply to be rejected up front. Name
+// conflicts are reported in the order they were first produced, followed by
+// node port conflicts ordered by port. Objects that are not Services are
+// ignored.
+func DetectServiceConflicts(objs []resource.Object) ConflictReport {
+	type nsName struct{ namespace, name string }
+
+	names := map[nsName][]string{}
+	nameOrder := []nsName{}
+	ports := map[int32][]string{}
+
+	for _, o := range objs {
+		s, ok := o.(*corev1.Service)
+		if !ok || s == nil {
+			continue
+		}
+		id := s.GetNamespace() + "/" + s.GetName()
+
+		k := nsName{namespace: s.GetNamespace(), name: s.GetName()}
+		if _, ok := names[k]; !ok {
+			nameOrder = append(nameOrder, k)
+		}
+		names[k] = append(names[k], id)
+
+		// A Service may expose the same node port for more than one
+		// protocol, so each Service claims each node port only once.
+		claimed := map[int32]bool{}
+		for _, p := range s.Spec.Ports {
+			if p.NodePort == 0 || claimed[p.NodePort] {
+				continue
+			}
+			claimed[p.NodePort] = true
+			ports[p.NodePort] = append(ports[p.NodePort], id)
+		}
+	}
+
+	r := ConflictReport{}
+	for _, k := range nameOrder {
+		if len(names[k]) < 2 {
+			continue
+		}
+		r.Conflicts = append(r.Conflicts, Conflict{
+			Kind:      ConflictKindServiceName,
+			Namespace: k.namespace,
+			Name:      k.name,
+			Services:  names[k],
+		})
+	}
+
+	np := make([]int32, 0, len(ports))
+	for p, ids := range ports {
+		if len(ids) > 1 {
+			np = append(np, p)
+		}
+	}
+	sort.Slice(np, func(i, j int) bool { return np[i] < np[j] })
+	for _, p := range np {
+		r.Conflicts = append(r.Conflicts, Conflict{
+			Kind:     ConflictKindNodePort,
+			NodePort: p,
+			Services: ports[p],
+		})
+	}
+	return r
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestDetectServiceConflicts(t *testing.T) {
+	inNamespace := func(ns string) serviceModifier {
+		return func(s *corev1.Service) {
+			s.SetNamespace(ns)
+		}
+	}
+
+	type want struct {
+		report ConflictReport
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		objs   []resource.Object
+		want   want
+	}{
+		"NoConflicts": {
+			reason: "Distinct Services with distinct node ports should not conflict. Objects that are not Services should be ignored.",
+			objs: []resource.Object{
+				deployment(),
+				service(inNamespace("a"), sWithContainerPort(80), sWithNodePort(80, 30080)),
+				service(inNamespace("b"), sWithContainerPort(80), sWithNodePort(80, 30081)),
+			},
+			want: want{},
+		},
+		"NameCollision": {
+			reason: "Services that share a namespace and name should be reported.",
+			objs: []resource.Object{
+				service(inNamespace("a")),
+				service(inNamespace("b")),
+				service(inNamespace("a")),
+			},
+			want: want{
+				report: ConflictReport{Conflicts: []Conflict{{
+					Kind:      ConflictKindServiceName,
+					Namespace: "a",
+					Name:      workloadName + ServiceNameSuffix,
+					Services:  []string{"a/" + workloadName + ServiceNameSuffix, "a/" + workloadName + ServiceNameSuffix},
+				}}},
+				err: errors.Errorf(errFmtConflicts, "Service a/"+workloadName+ServiceNameSuffix+" is produced 2 times"),
+			},
+		},
+		"NodePortCollision": {
+			reason: "Services in any namespace that claim the same node port should be reported. A Service that exposes a node port for two protocols should not conflict with itself.",
+			objs: []resource.Object{
+				service(inNamespace("a"),
+					sWithProtocolContainerPort(53, corev1.ProtocolTCP, "dns-tcp"),
+					sWithProtocolContainerPort(53, corev1.ProtocolUDP, "dns-udp"),
+					sWithNodePort(53, 30053),
+				),
+				service(inNamespace("b"), sWithName("other"), sWithContainerPort(80), sWithNodePort(80, 30053)),
+			},
+			want: want{
+				report: ConflictReport{Conflicts: []Conflict{{
+					Kind:     ConflictKindNodePort,
+					NodePort: 30053,
+					Services: []string{"a/" + workloadName + ServiceNameSuffix, "b/other"},
+				}}},
+				err: errors.Errorf(errFmtConflicts, "node port 30053 is claimed by a/"+workloadName+ServiceNameSuffix+", b/other"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := DetectServiceConflicts(tc.objs)
+			if diff := cmp.Diff(tc.want.report, got); diff != "" {
+				t.Errorf("\nReason: %s\nDetectServiceConflicts(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, got.Err(), test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nDetectServiceConflicts(...).Err(): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
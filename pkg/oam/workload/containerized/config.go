@@ -0,0 +1,131 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerized
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+const (
+	errFmtInvalidConfigFile   = "container %q: config file %q cannot be stored in a ConfigMap: %s"
+	errFmtDuplicateConfigFile = "container %q: config file %q conflicts with another of the container's config files"
+)
+
+// ConfigMapNameSuffix is appended to the name of a ContainerizedWorkload to
+// derive the name of the ConfigMap that holds its inline config files.
+const ConfigMapNameSuffix = "-config"
+
+// ConfigVolumeName is the name of the volume through which inline config files
+// are mounted. Volumes declared using AnnotationKeyVolumes may not use it.
+const ConfigVolumeName = "oam-config"
+
+var (
+	configMapKind       = reflect.TypeOf(corev1.ConfigMap{}).Name()
+	configMapAPIVersion = corev1.SchemeGroupVersion.String()
+)
+
+// configFiles returns a ConfigMap holding the inline config files of the
+// supplied containers, and the volume mounts that write each file to its path
+// keyed by container name. Config files without an inline value are skipped.
+// A nil ConfigMap is returned if there are no inline config files.
+func configFiles(labelKey string, cw *oamv1alpha2.ContainerizedWorkload, containers []oamv1alpha2.Container) (*corev1.ConfigMap, map[string][]corev1.VolumeMount, error) {
+	data := map[string]string{}
+	mounts := map[string][]corev1.VolumeMount{}
+	for _, c := range containers {
+		for _, f := range c.ConfigFiles {
+			if f.Value == nil {
+				continue
+			}
+			key := configFileKey(c.Name, f.Path)
+			if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+				return nil, nil, errors.Errorf(errFmtInvalidConfigFile, c.Name, f.Path, strings.Join(errs, ", "))
+			}
+			if _, ok := data[key]; ok {
+				return nil, nil, errors.Errorf(errFmtDuplicateConfigFile, c.Name, f.Path)
+			}
+			data[key] = *f.Value
+			mounts[c.Name] = append(mounts[c.Name], corev1.VolumeMount{
+				Name:      ConfigVolumeName,
+				MountPath: f.Path,
+				SubPath:   key,
+				ReadOnly:  true,
+			})
+		}
+	}
+	if len(data) == 0 {
+		return nil, nil, nil
+	}
+
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       configMapKind,
+			APIVersion: configMapAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: cw.GetName() + ConfigMapNameSuffix,
+			Labels: map[string]string{
+				labelKey: string(cw.GetUID()),
+			},
+		},
+		Data: data,
+	}
+	return cm, mounts, nil
+}
+
+// configFileKey returns the ConfigMap key under which the config file at the
+// supplied path of the supplied container is stored, for example
+// cool-container_etc_cool.conf for /etc/cool.conf. Characters that are not
+// valid in a ConfigMap key are replaced with underscores.
+func configFileKey(container, path string) string {
+	p := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, path)
+	return container + "_" + strings.Trim(p, "_")
+}
+
+// configVolume returns a volume that exposes the supplied ConfigMap.
+func configVolume(cm *corev1.ConfigMap) corev1.Volume {
+	return corev1.Volume{
+		Name: ConfigVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: cm.GetName()},
+			},
+		},
+	}
+}
+
+// addVolumeMounts appends the supplied volume mounts to the containers whose
+// names they are keyed by.
+func addVolumeMounts(mounts map[string][]corev1.VolumeMount, containers []corev1.Container) {
+	for i := range containers {
+		containers[i].VolumeMounts = append(containers[i].VolumeMounts, mounts[containers[i].Name]...)
+	}
+}
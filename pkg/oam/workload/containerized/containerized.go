@@ -18,7 +18,9 @@ package containerized
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
@@ -26,231 +28,666 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
+	runtimeworkload "github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+	"github.com/crossplane/crossplane/pkg/oam/workload"
 )
 
 // Reconcile error strings.
 const (
 	errNotContainerizedWorkload = "object is not a containerized workload"
+	errFmtAmbiguousEnvVar       = "container %q: environment variable %q may not set both a value and a value source"
+	errFmtEnvFromSource         = "container %q: envFrom source %d must reference exactly one of a ConfigMap or a Secret"
+	errFmtEnvFromName           = "container %q: envFrom source %d must specify the name of the %s it references"
+	errFmtStartupTooLong        = "container %q: startup probe allows %d seconds for the container to start, more than the maximum of %d"
 )
 
-const labelKey = "containerizedworkload.oam.crossplane.io"
+// Reasons OAM Container fields are not translated.
+const (
+	reasonVolumeDisk = "volume disk requirements are not supported and were dropped; declare volumes using the " + AnnotationKeyVolumes + " annotation"
+)
+
+// maxStartupSeconds is the longest a startup probe may allow a container to
+// take to start.
+const maxStartupSeconds = 60 * 60
+
+// Default probe timings, used when an OAM ContainerHealthProbe omits them.
+// These match the Kubernetes API server's defaults.
+const (
+	defaultProbePeriodSeconds    = 10
+	defaultProbeTimeoutSeconds   = 1
+	defaultProbeSuccessThreshold = 1
+	defaultProbeFailureThreshold = 3
+)
+
+// SelectorLabelKey is the label used to select the pods of a translated
+// Deployment or DaemonSet. Selectors are immutable once created, so it must
+// not change; pods are additionally labelled with workload.LabelKey, or the
+// label configured by WithLabelKey, which other wrappers and traits use to
+// select them.
+const SelectorLabelKey = "containerizedworkload.oam.crossplane.io"
+
+// DeploymentNameSuffix is appended to the name of a ContainerizedWorkload to
+// derive the name of the Deployment produced by TranslateContainerizedWorkload.
+const DeploymentNameSuffix = "-deployment"
 
 var (
 	deploymentKind       = reflect.TypeOf(appsv1.Deployment{}).Name()
 	deploymentAPIVersion = appsv1.SchemeGroupVersion.String()
 )
 
-// Translator translates a ContainerizedWorkload into a Deployment.
-// nolint:gocyclo
+// Translator translates a ContainerizedWorkload into a Deployment. The
+// Deployment is named after the ContainerizedWorkload, and selects pods
+// labelled with the workload's UID using SelectorLabelKey. Pods are also
+// labelled with the workload's UID using workload.LabelKey. A
+// ContainerizedWorkload that uses the OnFailure or Never restart policy is
+// instead translated into a Job with the same name and pod template, and one
+// that sets the AnnotationKeyDaemonSet annotation into a DaemonSet.
+//
+// Inline config files are stored in a ConfigMap named
+// <workload-name>-config, which is mounted into each container that declares
+// them. Config files sourced from secrets are mounted from those secrets.
+// Sensitive data supplied using the AnnotationKeySecretData annotation is
+// stored in a Secret named <workload-name>-secret. A ServiceAccount is
+// created if the workload requests one using the
+// AnnotationKeyCreateServiceAccount annotation. The ConfigMap, Secret, and
+// ServiceAccount are labelled with the workload's UID using the same label as
+// the pods.
 func Translator(ctx context.Context, w resource.Workload) ([]resource.Object, error) {
-	cw, ok := w.(*oamv1alpha2.ContainerizedWorkload)
-	if !ok {
-		return nil, errors.New(errNotContainerizedWorkload)
-	}
-
-	d := &appsv1.Deployment{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       deploymentKind,
-			APIVersion: deploymentAPIVersion,
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name: cw.GetName(),
-		},
-		Spec: appsv1.DeploymentSpec{
-			Selector: &metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					labelKey: string(cw.GetUID()),
-				},
+	return NewTranslator()(ctx, w)
+}
+
+// TranslateContainerizedWorkload translates a ContainerizedWorkload like
+// Translator, but names the Deployment <workload-name>-deployment. Jobs and
+// DaemonSets are still named after the ContainerizedWorkload.
+func TranslateContainerizedWorkload(ctx context.Context, w resource.Workload) ([]resource.Object, error) {
+	return NewTranslator(WithDeploymentNameSuffix(DeploymentNameSuffix))(ctx, w)
+}
+
+// A TranslatorOption configures a translator returned by NewTranslator.
+type TranslatorOption func(*translatorOptions)
+
+type translatorOptions struct {
+	labelKey             string
+	priorityClasses      map[string]bool
+	deploymentNameSuffix string
+}
+
+// WithLabelKey configures the label used to associate the pods and other
+// objects of a translation with their workload. workload.LabelKey is used by
+// default. The translated Deployment always selects its pods using
+// SelectorLabelKey, which cannot be configured.
+func WithLabelKey(k string) TranslatorOption {
+	return func(o *translatorOptions) {
+		o.labelKey = k
+	}
+}
+
+// WithPriorityClasses configures the names of the PriorityClasses that a
+// workload may request using AnnotationKeyPriorityClassName. Translation
+// fails if a workload requests any other priority class. Any valid name is
+// accepted by default. Names accumulate if this option is supplied more than
+// once.
+func WithPriorityClasses(names ...string) TranslatorOption {
+	return func(o *translatorOptions) {
+		if o.priorityClasses == nil {
+			o.priorityClasses = map[string]bool{}
+		}
+		for _, n := range names {
+			o.priorityClasses[n] = true
+		}
+	}
+}
+
+// WithDeploymentNameSuffix configures a suffix that is appended to the name of
+// the ContainerizedWorkload to derive the name of the translated Deployment.
+// The Deployment is named after the ContainerizedWorkload by default. It does
+// not affect Jobs or DaemonSets.
+func WithDeploymentNameSuffix(suffix string) TranslatorOption {
+	return func(o *translatorOptions) {
+		o.deploymentNameSuffix = suffix
+	}
+}
+
+// NewTranslator returns a translator that behaves like Translator, configured
+// by the supplied options.
+func NewTranslator(options ...TranslatorOption) runtimeworkload.TranslateFn {
+	opts := translatorOptions{labelKey: workload.LabelKey}
+	for _, fn := range options {
+		fn(&opts)
+	}
+	return func(ctx context.Context, w resource.Workload) ([]resource.Object, error) {
+		cw, ok := w.(*oamv1alpha2.ContainerizedWorkload)
+		if !ok {
+			return nil, errors.New(errNotContainerizedWorkload)
+		}
+
+		d := &appsv1.Deployment{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       deploymentKind,
+				APIVersion: deploymentAPIVersion,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: cw.GetName(),
 			},
-			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						labelKey: string(cw.GetUID()),
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						SelectorLabelKey: string(cw.GetUID()),
+					},
+				},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							SelectorLabelKey: string(cw.GetUID()),
+							opts.labelKey:    string(cw.GetUID()),
+						},
 					},
 				},
 			},
-		},
-	}
-	if cw.Spec.OperatingSystem != nil {
-		if d.Spec.Template.Spec.NodeSelector == nil {
-			d.Spec.Template.Spec.NodeSelector = map[string]string{}
 		}
-		d.Spec.Template.Spec.NodeSelector["beta.kubernetes.io/os"] = string(*cw.Spec.OperatingSystem)
-	}
+		if cw.Spec.OperatingSystem != nil {
+			if d.Spec.Template.Spec.NodeSelector == nil {
+				d.Spec.Template.Spec.NodeSelector = map[string]string{}
+			}
+			d.Spec.Template.Spec.NodeSelector["beta.kubernetes.io/os"] = string(*cw.Spec.OperatingSystem)
+		}
 
-	if cw.Spec.CPUArchitecture != nil {
-		if d.Spec.Template.Spec.NodeSelector == nil {
-			d.Spec.Template.Spec.NodeSelector = map[string]string{}
+		if cw.Spec.CPUArchitecture != nil {
+			if d.Spec.Template.Spec.NodeSelector == nil {
+				d.Spec.Template.Spec.NodeSelector = map[string]string{}
+			}
+			d.Spec.Template.Spec.NodeSelector["kubernetes.io/arch"] = string(*cw.Spec.CPUArchitecture)
 		}
-		d.Spec.Template.Spec.NodeSelector["kubernetes.io/arch"] = string(*cw.Spec.CPUArchitecture)
-	}
 
-	for _, container := range cw.Spec.Containers {
-		if container.ImagePullSecret != nil {
-			d.Spec.Template.Spec.ImagePullSecrets = append(d.Spec.Template.Spec.ImagePullSecrets, corev1.LocalObjectReference{
-				Name: *container.ImagePullSecret,
-			})
+		r, err := replicas(cw)
+		if err != nil {
+			return nil, err
 		}
-		kubernetesContainer := corev1.Container{
-			Name:    container.Name,
-			Image:   container.Image,
-			Command: container.Command,
-			Args:    container.Arguments,
+		d.Spec.Replicas = r
+
+		st, err := updateStrategy(cw)
+		if err != nil {
+			return nil, err
 		}
+		d.Spec.Strategy = st
 
-		if container.Resources != nil {
-			kubernetesContainer.Resources = corev1.ResourceRequirements{
-				Requests: corev1.ResourceList{
-					corev1.ResourceCPU:    container.Resources.CPU.Required,
-					corev1.ResourceMemory: container.Resources.Memory.Required,
-				},
+		rl, err := revisionHistoryLimit(cw)
+		if err != nil {
+			return nil, err
+		}
+		d.Spec.RevisionHistoryLimit = rl
+
+		mr, err := minReadySeconds(cw)
+		if err != nil {
+			return nil, err
+		}
+		d.Spec.MinReadySeconds = mr
+
+		pd, err := progressDeadline(cw, mr)
+		if err != nil {
+			return nil, err
+		}
+		d.Spec.ProgressDeadlineSeconds = pd
+
+		overrides, err := containerOverrides(cw)
+		if err != nil {
+			return nil, err
+		}
+
+		inits, err := initContainers(cw)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, container := range inits {
+			c, err := translateContainer(ctx, container, overrides[container.Name])
+			if err != nil {
+				return nil, err
 			}
-			for _, v := range container.Resources.Volumes {
-				mount := corev1.VolumeMount{
-					Name:      v.Name,
-					MountPath: v.MouthPath,
-				}
-				if v.AccessMode != nil && *v.AccessMode == oamv1alpha2.VolumeAccessModeRO {
-					mount.ReadOnly = true
-				}
-				kubernetesContainer.VolumeMounts = append(kubernetesContainer.VolumeMounts, mount)
+			d.Spec.Template.Spec.InitContainers = append(d.Spec.Template.Spec.InitContainers, c)
+		}
 
+		for _, container := range cw.Spec.Containers {
+			c, err := translateContainer(ctx, container, overrides[container.Name])
+			if err != nil {
+				return nil, err
 			}
+			d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, c)
+		}
+
+		d.Spec.Template.Spec.ImagePullSecrets = imagePullSecrets(cw, inits)
+
+		sc, err := podSecurityContext(cw)
+		if err != nil {
+			return nil, err
+		}
+		d.Spec.Template.Spec.SecurityContext = sc
+
+		gp, err := terminationGracePeriod(cw)
+		if err != nil {
+			return nil, err
+		}
+		d.Spec.Template.Spec.TerminationGracePeriodSeconds = gp
+
+		ha, err := hostAliases(cw)
+		if err != nil {
+			return nil, err
+		}
+		d.Spec.Template.Spec.HostAliases = ha
+
+		pc, err := priorityClassName(cw, opts.priorityClasses)
+		if err != nil {
+			return nil, err
+		}
+		d.Spec.Template.Spec.PriorityClassName = pc
+
+		rg, err := readinessGates(cw)
+		if err != nil {
+			return nil, err
+		}
+		d.Spec.Template.Spec.ReadinessGates = rg
+
+		dp, dc, err := dns(cw)
+		if err != nil {
+			return nil, err
+		}
+		d.Spec.Template.Spec.DNSPolicy = dp
+		d.Spec.Template.Spec.DNSConfig = dc
+
+		if err := hostNamespaces(ctx, cw, &d.Spec.Template.Spec); err != nil {
+			return nil, err
+		}
+
+		saName, sa, err := serviceAccount(opts.labelKey, cw)
+		if err != nil {
+			return nil, err
 		}
+		d.Spec.Template.Spec.ServiceAccountName = saName
 
-		for _, p := range container.Ports {
-			port := corev1.ContainerPort{
-				Name:          p.Name,
-				ContainerPort: p.Port,
+		vols, err := volumes(cw)
+		if err != nil {
+			return nil, err
+		}
+		ps := &d.Spec.Template.Spec
+		// Mounts are only validated against volumes declared using the
+		// annotation. Workloads that don't use it may mount volumes supplied
+		// by other means, as they always could.
+		if _, ok := cw.GetAnnotations()[AnnotationKeyVolumes]; ok {
+			if err := validateVolumeMounts(vols, append(ps.InitContainers, ps.Containers...)...); err != nil {
+				return nil, err
 			}
-			if p.Protocol != nil {
-				port.Protocol = corev1.Protocol(*p.Protocol)
+		}
+
+		cm, mounts, err := configFiles(opts.labelKey, cw, append(inits, cw.Spec.Containers...))
+		if err != nil {
+			return nil, err
+		}
+		if cm != nil {
+			vols = append(vols, configVolume(cm))
+			addVolumeMounts(mounts, ps.InitContainers)
+			addVolumeMounts(mounts, ps.Containers)
+		}
+
+		svols, smounts := secretFiles(append(inits, cw.Spec.Containers...))
+		vols = append(vols, svols...)
+		addVolumeMounts(smounts, ps.InitContainers)
+		addVolumeMounts(smounts, ps.Containers)
+		ps.Volumes = vols
+
+		sec, err := secretData(opts.labelKey, cw)
+		if err != nil {
+			return nil, err
+		}
+
+		var objs []resource.Object
+		policy, err := restartPolicy(cw)
+		if err != nil {
+			return nil, err
+		}
+		ds, err := isDaemonSet(cw)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case ds && policy != corev1.RestartPolicyAlways:
+			return nil, errors.New(errDaemonSetRestartPolicy)
+		case ds:
+			objs = append(objs, daemonSet(d))
+		case policy != corev1.RestartPolicyAlways:
+			j, err := job(d, policy)
+			if err != nil {
+				return nil, err
 			}
-			kubernetesContainer.Ports = append(kubernetesContainer.Ports, port)
+			objs = append(objs, j)
+		default:
+			d.SetName(cw.GetName() + opts.deploymentNameSuffix)
+			objs = append(objs, d)
+		}
+
+		if cm != nil {
+			objs = append(objs, cm)
+		}
+		if sec != nil {
+			objs = append(objs, sec)
+		}
+		if sa != nil {
+			objs = append(objs, sa)
 		}
+		return objs, nil
+	}
+}
+
+// translateContainer translates an OAM Container into a Kubernetes Container,
+// applying the supplied overrides. An error is returned if the container's
+// image is not a valid image reference. Fields of the OAM Container that
+// cannot be translated are recorded as warnings.
+// nolint:gocyclo
+func translateContainer(ctx context.Context, container oamv1alpha2.Container, o ContainerOverrides) (corev1.Container, error) {
+	if err := validateImage(ctx, container); err != nil {
+		return corev1.Container{}, err
+	}
+	warnUntranslated(ctx, container)
+
+	kubernetesContainer := corev1.Container{
+		Name:    container.Name,
+		Image:   container.Image,
+		Command: copyStrings(container.Command),
+		Args:    copyStrings(container.Arguments),
+	}
 
-		for _, e := range container.Environment {
-			if e.Value != nil {
-				kubernetesContainer.Env = append(kubernetesContainer.Env, corev1.EnvVar{
-					Name:  e.Name,
-					Value: *e.Value,
-				})
-				continue
+	if container.Resources != nil {
+		kubernetesContainer.Resources = corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    container.Resources.CPU.Required,
+				corev1.ResourceMemory: container.Resources.Memory.Required,
+			},
+		}
+		ext, err := extendedResources(container)
+		if err != nil {
+			return corev1.Container{}, err
+		}
+		for name, q := range ext {
+			kubernetesContainer.Resources.Requests[name] = q
+		}
+		for _, v := range container.Resources.Volumes {
+			mount := corev1.VolumeMount{
+				Name:      v.Name,
+				MountPath: v.MouthPath,
+			}
+			if v.AccessMode != nil && *v.AccessMode == oamv1alpha2.VolumeAccessModeRO {
+				mount.ReadOnly = true
 			}
-			if e.FromSecret != nil {
-				kubernetesContainer.Env = append(kubernetesContainer.Env, corev1.EnvVar{
-					Name: e.Name,
-					ValueFrom: &corev1.EnvVarSource{
-						SecretKeyRef: &corev1.SecretKeySelector{
-							Key: e.FromSecret.Key,
-							LocalObjectReference: corev1.LocalObjectReference{
-								Name: e.FromSecret.Name,
-							},
+			kubernetesContainer.VolumeMounts = append(kubernetesContainer.VolumeMounts, mount)
+
+		}
+	}
+
+	requests, err := resourceList(container.Name, "requests", o.Requests)
+	if err != nil {
+		return corev1.Container{}, err
+	}
+	for name, q := range requests {
+		if kubernetesContainer.Resources.Requests == nil {
+			kubernetesContainer.Resources.Requests = corev1.ResourceList{}
+		}
+		kubernetesContainer.Resources.Requests[name] = q
+	}
+
+	limits, err := resourceList(container.Name, "limits", o.Limits)
+	if err != nil {
+		return corev1.Container{}, err
+	}
+	kubernetesContainer.Resources.Limits = limits
+
+	// Extended resources may not be overcommitted, so Kubernetes requires
+	// their limits to equal their requests.
+	for name, q := range kubernetesContainer.Resources.Requests {
+		if !isExtendedResource(name) {
+			continue
+		}
+		if _, ok := kubernetesContainer.Resources.Limits[name]; ok {
+			continue
+		}
+		if kubernetesContainer.Resources.Limits == nil {
+			kubernetesContainer.Resources.Limits = corev1.ResourceList{}
+		}
+		kubernetesContainer.Resources.Limits[name] = q
+	}
+
+	for _, p := range container.Ports {
+		port := corev1.ContainerPort{
+			Name:          p.Name,
+			ContainerPort: p.Port,
+		}
+		if p.Protocol != nil {
+			port.Protocol = corev1.Protocol(*p.Protocol)
+		}
+		kubernetesContainer.Ports = append(kubernetesContainer.Ports, port)
+	}
+
+	env, err := envVars(container, o.Env)
+	if err != nil {
+		return corev1.Container{}, err
+	}
+	kubernetesContainer.Env = env
+
+	ef, err := envFrom(container.Name, o.EnvFrom)
+	if err != nil {
+		return corev1.Container{}, err
+	}
+	kubernetesContainer.EnvFrom = ef
+
+	kubernetesContainer.SecurityContext = o.SecurityContext
+	kubernetesContainer.Stdin = o.Stdin
+	kubernetesContainer.StdinOnce = o.StdinOnce
+	kubernetesContainer.TTY = o.TTY
+
+	lc, err := lifecycle(container.Name, o.Lifecycle)
+	if err != nil {
+		return corev1.Container{}, err
+	}
+	kubernetesContainer.Lifecycle = lc
+
+	kubernetesContainer.LivenessProbe = probe(container.LivenessProbe)
+	kubernetesContainer.ReadinessProbe = probe(container.ReadinessProbe)
+
+	sp, err := startupProbe(container.Name, o.StartupProbe)
+	if err != nil {
+		return corev1.Container{}, err
+	}
+	kubernetesContainer.StartupProbe = sp
+
+	return kubernetesContainer, nil
+}
+
+// envVars translates the environment variables of an OAM Container, followed
+// by the supplied override variables, into Kubernetes environment variables.
+// OAM variables that set neither a value nor a secret are skipped.
+func envVars(container oamv1alpha2.Container, overrides []corev1.EnvVar) ([]corev1.EnvVar, error) {
+	var env []corev1.EnvVar
+	for _, e := range container.Environment {
+		if e.Value != nil && e.FromSecret != nil {
+			return nil, errors.Errorf(errFmtAmbiguousEnvVar, container.Name, e.Name)
+		}
+		if e.Value != nil {
+			env = append(env, corev1.EnvVar{
+				Name:  e.Name,
+				Value: *e.Value,
+			})
+			continue
+		}
+		if e.FromSecret != nil {
+			env = append(env, corev1.EnvVar{
+				Name: e.Name,
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						Key: e.FromSecret.Key,
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: e.FromSecret.Name,
 						},
 					},
-				})
-			}
+				},
+			})
+		}
+	}
+	for _, e := range overrides {
+		if e.Value != "" && e.ValueFrom != nil {
+			return nil, errors.Errorf(errFmtAmbiguousEnvVar, container.Name, e.Name)
 		}
+		env = append(env, e)
+	}
+	return env, nil
+}
 
-		if container.LivenessProbe != nil {
-			kubernetesContainer.LivenessProbe = &corev1.Probe{}
-			if container.LivenessProbe.InitialDelaySeconds != nil {
-				kubernetesContainer.LivenessProbe.InitialDelaySeconds = *container.LivenessProbe.InitialDelaySeconds
-			}
-			if container.LivenessProbe.TimeoutSeconds != nil {
-				kubernetesContainer.LivenessProbe.TimeoutSeconds = *container.LivenessProbe.TimeoutSeconds
-			}
-			if container.LivenessProbe.PeriodSeconds != nil {
-				kubernetesContainer.LivenessProbe.PeriodSeconds = *container.LivenessProbe.PeriodSeconds
-			}
-			if container.LivenessProbe.SuccessThreshold != nil {
-				kubernetesContainer.LivenessProbe.SuccessThreshold = *container.LivenessProbe.SuccessThreshold
-			}
-			if container.LivenessProbe.FailureThreshold != nil {
-				kubernetesContainer.LivenessProbe.FailureThreshold = *container.LivenessProbe.FailureThreshold
-			}
+// envFrom validates the supplied sources of environment variables for the
+// named container. Each source must reference exactly one named ConfigMap or
+// Secret.
+func envFrom(container string, sources []corev1.EnvFromSource) ([]corev1.EnvFromSource, error) {
+	for i, s := range sources {
+		switch {
+		case (s.ConfigMapRef == nil) == (s.SecretRef == nil):
+			return nil, errors.Errorf(errFmtEnvFromSource, container, i)
+		case s.ConfigMapRef != nil && s.ConfigMapRef.Name == "":
+			return nil, errors.Errorf(errFmtEnvFromName, container, i, "ConfigMap")
+		case s.SecretRef != nil && s.SecretRef.Name == "":
+			return nil, errors.Errorf(errFmtEnvFromName, container, i, "Secret")
+		}
+	}
+	return sources, nil
+}
 
-			// NOTE(hasheddan): Kubernetes specifies that only one type of
-			// handler should be provided. OAM does not impose that same
-			// restriction. We optimistically check all and set whatever is
-			// provided.
-			if container.LivenessProbe.HTTPGet != nil {
-				kubernetesContainer.LivenessProbe.Handler.HTTPGet = &corev1.HTTPGetAction{
-					Path: container.LivenessProbe.HTTPGet.Path,
-					Port: intstr.IntOrString{IntVal: container.LivenessProbe.HTTPGet.Port},
-				}
-
-				for _, h := range container.LivenessProbe.HTTPGet.HTTPHeaders {
-					kubernetesContainer.LivenessProbe.Handler.HTTPGet.HTTPHeaders = append(kubernetesContainer.LivenessProbe.Handler.HTTPGet.HTTPHeaders, corev1.HTTPHeader{
-						Name:  h.Name,
-						Value: h.Value,
-					})
-				}
-			}
-			if container.LivenessProbe.Exec != nil {
-				kubernetesContainer.LivenessProbe.Exec = &corev1.ExecAction{
-					Command: container.LivenessProbe.Exec.Command,
-				}
-			}
-			if container.LivenessProbe.TCPSocket != nil {
-				kubernetesContainer.LivenessProbe.TCPSocket = &corev1.TCPSocketAction{
-					Port: intstr.IntOrString{IntVal: container.LivenessProbe.TCPSocket.Port},
-				}
-			}
+// probe translates an OAM ContainerHealthProbe into a Kubernetes Probe. Unset
+// timings are defaulted. A nil ContainerHealthProbe produces a nil Probe.
+func probe(p *oamv1alpha2.ContainerHealthProbe) *corev1.Probe {
+	if p == nil {
+		return nil
+	}
+
+	kp := &corev1.Probe{
+		PeriodSeconds:    defaultProbePeriodSeconds,
+		TimeoutSeconds:   defaultProbeTimeoutSeconds,
+		SuccessThreshold: defaultProbeSuccessThreshold,
+		FailureThreshold: defaultProbeFailureThreshold,
+	}
+	if p.InitialDelaySeconds != nil {
+		kp.InitialDelaySeconds = *p.InitialDelaySeconds
+	}
+	if p.TimeoutSeconds != nil {
+		kp.TimeoutSeconds = *p.TimeoutSeconds
+	}
+	if p.PeriodSeconds != nil {
+		kp.PeriodSeconds = *p.PeriodSeconds
+	}
+	if p.SuccessThreshold != nil {
+		kp.SuccessThreshold = *p.SuccessThreshold
+	}
+	if p.FailureThreshold != nil {
+		kp.FailureThreshold = *p.FailureThreshold
+	}
+
+	// NOTE(hasheddan): Kubernetes specifies that only one type of handler
+	// should be provided. OAM does not impose that same restriction. We
+	// optimistically check all and set whatever is provided.
+	if p.HTTPGet != nil {
+		kp.HTTPGet = httpGetAction(p.HTTPGet)
+	}
+	if p.Exec != nil {
+		kp.Exec = &corev1.ExecAction{
+			Command: p.Exec.Command,
+		}
+	}
+	if p.TCPSocket != nil {
+		kp.TCPSocket = &corev1.TCPSocketAction{
+			Port: intstr.FromInt(int(p.TCPSocket.Port)),
 		}
+	}
+	return kp
+}
 
-		if container.ReadinessProbe != nil {
-			kubernetesContainer.ReadinessProbe = &corev1.Probe{}
-			if container.ReadinessProbe.InitialDelaySeconds != nil {
-				kubernetesContainer.ReadinessProbe.InitialDelaySeconds = *container.ReadinessProbe.InitialDelaySeconds
-			}
-			if container.ReadinessProbe.TimeoutSeconds != nil {
-				kubernetesContainer.ReadinessProbe.TimeoutSeconds = *container.ReadinessProbe.TimeoutSeconds
-			}
-			if container.ReadinessProbe.PeriodSeconds != nil {
-				kubernetesContainer.ReadinessProbe.PeriodSeconds = *container.ReadinessProbe.PeriodSeconds
-			}
-			if container.ReadinessProbe.SuccessThreshold != nil {
-				kubernetesContainer.ReadinessProbe.SuccessThreshold = *container.ReadinessProbe.SuccessThreshold
-			}
-			if container.ReadinessProbe.FailureThreshold != nil {
-				kubernetesContainer.ReadinessProbe.FailureThreshold = *container.ReadinessProbe.FailureThreshold
-			}
+// startupProbe translates the supplied startup probe of the named container
+// into a Kubernetes Probe. An error is returned if the probe would allow the
+// container more than maxStartupSeconds to start.
+func startupProbe(container string, p *oamv1alpha2.ContainerHealthProbe) (*corev1.Probe, error) {
+	sp := probe(p)
+	if sp == nil {
+		return nil, nil
+	}
+	if max := int64(sp.FailureThreshold) * int64(sp.PeriodSeconds); max > maxStartupSeconds {
+		return nil, errors.Errorf(errFmtStartupTooLong, container, max, maxStartupSeconds)
+	}
+	return sp, nil
+}
 
-			// NOTE(hasheddan): Kubernetes specifies that only one type of
-			// handler should be provided. OAM does not impose that same
-			// restriction. We optimistically check all and set whatever is
-			// provided.
-			if container.ReadinessProbe.HTTPGet != nil {
-				kubernetesContainer.ReadinessProbe.Handler.HTTPGet = &corev1.HTTPGetAction{
-					Path: container.ReadinessProbe.HTTPGet.Path,
-					Port: intstr.IntOrString{IntVal: container.ReadinessProbe.HTTPGet.Port},
-				}
-
-				for _, h := range container.ReadinessProbe.HTTPGet.HTTPHeaders {
-					kubernetesContainer.ReadinessProbe.Handler.HTTPGet.HTTPHeaders = append(kubernetesContainer.ReadinessProbe.Handler.HTTPGet.HTTPHeaders, corev1.HTTPHeader{
-						Name:  h.Name,
-						Value: h.Value,
-					})
-				}
-			}
-			if container.ReadinessProbe.Exec != nil {
-				kubernetesContainer.ReadinessProbe.Exec = &corev1.ExecAction{
-					Command: container.ReadinessProbe.Exec.Command,
-				}
-			}
-			if container.ReadinessProbe.TCPSocket != nil {
-				kubernetesContainer.ReadinessProbe.TCPSocket = &corev1.TCPSocketAction{
-					Port: intstr.IntOrString{IntVal: container.ReadinessProbe.TCPSocket.Port},
-				}
-			}
+// httpGetAction translates an OAM HTTPGetProbe into a Kubernetes
+// HTTPGetAction.
+func httpGetAction(p *oamv1alpha2.HTTPGetProbe) *corev1.HTTPGetAction {
+	a := &corev1.HTTPGetAction{
+		Path: p.Path,
+		Port: intstr.FromInt(int(p.Port)),
+	}
+	for _, h := range p.HTTPHeaders {
+		a.HTTPHeaders = append(a.HTTPHeaders, corev1.HTTPHeader{
+			Name:  h.Name,
+			Value: h.Value,
+		})
+	}
+	return a
+}
+
+// imagePullSecrets returns references to the image pull secrets of the
+// supplied ContainerizedWorkload's init containers and containers, followed by
+// those of its image pull secrets annotation. Empty and duplicate names are
+// omitted.
+func imagePullSecrets(cw *oamv1alpha2.ContainerizedWorkload, inits []oamv1alpha2.Container) []corev1.LocalObjectReference {
+	var names []string
+	for _, c := range append(inits, cw.Spec.Containers...) {
+		if c.ImagePullSecret != nil {
+			names = append(names, *c.ImagePullSecret)
 		}
+	}
+	if v, ok := cw.GetAnnotations()[AnnotationKeyImagePullSecrets]; ok {
+		names = append(names, strings.Split(v, ",")...)
+	}
 
-		d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, kubernetesContainer)
+	var refs []corev1.LocalObjectReference
+	seen := map[string]bool{}
+	for _, n := range names {
+		n = strings.TrimSpace(n)
+		if n == "" || seen[n] {
+			continue
+		}
+		seen[n] = true
+		refs = append(refs, corev1.LocalObjectReference{Name: n})
 	}
+	return refs
+}
 
-	return []resource.Object{d}, nil
+// copyStrings returns a copy of the supplied slice. A nil slice is copied as
+// nil and an empty slice as empty, so that an explicitly empty command or
+// argument list is distinguishable from an omitted one.
+func copyStrings(in []string) []string {
+	if in == nil {
+		return nil
+	}
+	out := make([]string, len(in))
+	copy(out, in)
+	return out
+}
+
+// warnUntranslated records a warning for each field of the supplied OAM
+// Container that is not translated.
+func warnUntranslated(ctx context.Context, c oamv1alpha2.Container) {
+	field := func(f string) string { return fmt.Sprintf("spec.containers[%s].%s", c.Name, f) }
+
+	if c.Resources == nil {
+		return
+	}
+	for _, v := range c.Resources.Volumes {
+		if v.Disk != nil {
+			workload.RecordWarning(ctx, workload.Warning{Field: field("resources.volumes[" + v.Name + "].disk"), Reason: reasonVolumeDisk})
+		}
+	}
 }
@@ -18,14 +18,21 @@ package containerized
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 	"testing"
 
+	"github.com/docker/distribution/reference"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	kresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -33,6 +40,9 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
+	"github.com/crossplane/crossplane/pkg/oam/trait/manualscaler"
+	oamworkload "github.com/crossplane/crossplane/pkg/oam/workload"
 )
 
 var (
@@ -58,6 +68,108 @@ func dmWithContainer(c corev1.Container) deploymentModifier {
 	}
 }
 
+func dmWithInitContainer(c corev1.Container) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.InitContainers = append(d.Spec.Template.Spec.InitContainers, c)
+	}
+}
+
+func dmWithVolume(v corev1.Volume) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.Volumes = append(d.Spec.Template.Spec.Volumes, v)
+	}
+}
+
+func dmWithImagePullSecret(name string) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.ImagePullSecrets = append(d.Spec.Template.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: name})
+	}
+}
+
+func dmWithReplicas(r int32) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Replicas = &r
+	}
+}
+
+func dmWithPodSecurityContext(sc *corev1.PodSecurityContext) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.SecurityContext = sc
+	}
+}
+
+func dmWithTerminationGracePeriod(s int64) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.TerminationGracePeriodSeconds = &s
+	}
+}
+
+func dmWithHostAliases(a ...corev1.HostAlias) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.HostAliases = append(d.Spec.Template.Spec.HostAliases, a...)
+	}
+}
+
+func dmWithServiceAccount(name string) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.ServiceAccountName = name
+	}
+}
+
+func dmWithStrategy(s appsv1.DeploymentStrategy) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Strategy = s
+	}
+}
+
+func dmWithRevisionHistoryLimit(l int32) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.RevisionHistoryLimit = &l
+	}
+}
+
+func dmWithMinReadySeconds(s int32) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.MinReadySeconds = s
+	}
+}
+
+func dmWithProgressDeadline(minReady, deadline int32) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.MinReadySeconds = minReady
+		d.Spec.ProgressDeadlineSeconds = &deadline
+	}
+}
+
+func dmWithPriorityClassName(name string) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.PriorityClassName = name
+	}
+}
+
+func dmWithDNS(p corev1.DNSPolicy, cfg *corev1.PodDNSConfig) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.DNSPolicy = p
+		d.Spec.Template.Spec.DNSConfig = cfg
+	}
+}
+
+func dmWithHostNamespaces(network, pid, ipc bool) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.HostNetwork = network
+		d.Spec.Template.Spec.HostPID = pid
+		d.Spec.Template.Spec.HostIPC = ipc
+	}
+}
+
+func dmWithReadinessGates(types ...corev1.PodConditionType) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		for _, t := range types {
+			d.Spec.Template.Spec.ReadinessGates = append(d.Spec.Template.Spec.ReadinessGates, corev1.PodReadinessGate{ConditionType: t})
+		}
+	}
+}
+
 func deployment(mod ...deploymentModifier) *appsv1.Deployment {
 	d := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
@@ -70,13 +182,14 @@ func deployment(mod ...deploymentModifier) *appsv1.Deployment {
 		Spec: appsv1.DeploymentSpec{
 			Selector: &metav1.LabelSelector{
 				MatchLabels: map[string]string{
-					labelKey: cwUID,
+					SelectorLabelKey: cwUID,
 				},
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: map[string]string{
-						labelKey: cwUID,
+						SelectorLabelKey:     cwUID,
+						oamworkload.LabelKey: cwUID,
 					},
 				},
 			},
@@ -90,6 +203,39 @@ func deployment(mod ...deploymentModifier) *appsv1.Deployment {
 	return d
 }
 
+func configMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       configMapKind,
+			APIVersion: configMapAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: cwName + ConfigMapNameSuffix,
+			Labels: map[string]string{
+				oamworkload.LabelKey: cwUID,
+			},
+		},
+		Data: data,
+	}
+}
+
+func secret(data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       secretKind,
+			APIVersion: secretAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: cwName + SecretNameSuffix,
+			Labels: map[string]string{
+				oamworkload.LabelKey: cwUID,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+}
+
 type cwModifier func(*oamv1alpha2.ContainerizedWorkload)
 
 func cwWithOS(os string) cwModifier {
@@ -105,6 +251,15 @@ func cwWithContainer(c oamv1alpha2.Container) cwModifier {
 	}
 }
 
+func cwWithAnnotation(k, v string) cwModifier {
+	return func(cw *oamv1alpha2.ContainerizedWorkload) {
+		if cw.Annotations == nil {
+			cw.Annotations = map[string]string{}
+		}
+		cw.Annotations[k] = v
+	}
+}
+
 func containerizedWorkload(mod ...cwModifier) *oamv1alpha2.ContainerizedWorkload {
 	cw := &oamv1alpha2.ContainerizedWorkload{
 		ObjectMeta: metav1.ObjectMeta{
@@ -121,11 +276,27 @@ func containerizedWorkload(mod ...cwModifier) *oamv1alpha2.ContainerizedWorkload
 	return cw
 }
 
-var _ workload.Translator = workload.TranslateFn(Translator)
+var (
+	_ workload.Translator = workload.TranslateFn(Translator)
+	_ workload.Translator = workload.TranslateFn(TranslateContainerizedWorkload)
+)
 
 func TestTranslator(t *testing.T) {
 
 	envVarSecretVal := "nicesecretvalue"
+	configVal := "cool: config"
+	configMapVolume := corev1.Volume{
+		Name: ConfigVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: cwName + ConfigMapNameSuffix},
+			},
+		},
+	}
+	imagePullSecret := "cool-secret"
+	yes, no := true, false
+	fsGroup := int64(2000)
+	maxSurge, maxUnavailable := intstr.FromString("50%"), intstr.FromInt(0)
 
 	type args struct {
 		w resource.Workload
@@ -196,15 +367,6 @@ func TestTranslator(t *testing.T) {
 							Name:  "NICE_SECRET",
 							Value: &envVarSecretVal,
 						},
-						// If both Value and FromSecret are defined, we use Value
-						{
-							Name:  "USE_VAL_SECRET",
-							Value: &envVarSecretVal,
-							FromSecret: &oamv1alpha2.SecretKeySelector{
-								Name: "cool-secret",
-								Key:  "secretdata",
-							},
-						},
 						// If neither Value or FromSecret is define, we skip
 						{
 							Name: "USE_VAL_SECRET",
@@ -255,25 +417,1980 @@ func TestTranslator(t *testing.T) {
 						Name:  "NICE_SECRET",
 						Value: envVarSecretVal,
 					},
+				},
+			}))}},
+		},
+		"SuccessfulSingleContainer": {
+			reason: "A ContainerizedWorkload with one container should be translated into a deployment with one container.",
+			args: args{
+				w: containerizedWorkload(cwWithContainer(oamv1alpha2.Container{
+					Name:  "cool-container",
+					Image: "cool/image:latest",
+				})),
+			},
+			want: want{result: []resource.Object{deployment(dmWithContainer(corev1.Container{
+				Name:  "cool-container",
+				Image: "cool/image:latest",
+			}))}},
+		},
+		"SuccessfulMultipleContainers": {
+			reason: "A ContainerizedWorkload with multiple containers should be translated into a deployment with the same containers, in order.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+						Ports: []oamv1alpha2.ContainerPort{{Name: "http", Port: 8080}},
+					}),
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-sidecar",
+						Image: "cool/sidecar:latest",
+						Ports: []oamv1alpha2.ContainerPort{{Name: "metrics", Port: 9090}},
+					}),
+				),
+			},
+			want: want{result: []resource.Object{deployment(
+				dmWithContainer(corev1.Container{
+					Name:  "cool-container",
+					Image: "cool/image:latest",
+					Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+				}),
+				dmWithContainer(corev1.Container{
+					Name:  "cool-sidecar",
+					Image: "cool/sidecar:latest",
+					Ports: []corev1.ContainerPort{{Name: "metrics", ContainerPort: 9090}},
+				}),
+			)}},
+		},
+		"SuccessfulRequestsOnly": {
+			reason: "Resource requests should be translated from the OAM container and its overrides.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+						Resources: &oamv1alpha2.ContainerResources{
+							CPU:    oamv1alpha2.CPUResources{Required: kresource.MustParse("500m")},
+							Memory: oamv1alpha2.MemoryResources{Required: kresource.MustParse("64Mi")},
+						},
+					}),
+					cwWithAnnotation(AnnotationKeyContainerOverrides, `{"cool-container":{"requests":{"memory":"128Mi","ephemeral-storage":"1Gi"}}}`),
+				),
+			},
+			want: want{result: []resource.Object{deployment(dmWithContainer(corev1.Container{
+				Name:  "cool-container",
+				Image: "cool/image:latest",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:              kresource.MustParse("500m"),
+						corev1.ResourceMemory:           kresource.MustParse("128Mi"),
+						corev1.ResourceEphemeralStorage: kresource.MustParse("1Gi"),
+					},
+				},
+			}))}},
+		},
+		"SuccessfulLimitsOnly": {
+			reason: "Resource limits should be translated from the container overrides.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+					}),
+					cwWithAnnotation(AnnotationKeyContainerOverrides, `{"cool-container":{"limits":{"cpu":"1","memory":"256Mi"}}}`),
+				),
+			},
+			want: want{result: []resource.Object{deployment(dmWithContainer(corev1.Container{
+				Name:  "cool-container",
+				Image: "cool/image:latest",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    kresource.MustParse("1"),
+						corev1.ResourceMemory: kresource.MustParse("256Mi"),
+					},
+				},
+			}))}},
+		},
+		"SuccessfulRequestsAndLimits": {
+			reason: "Resource requests and limits should both be translated.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+						Resources: &oamv1alpha2.ContainerResources{
+							CPU:    oamv1alpha2.CPUResources{Required: kresource.MustParse("500m")},
+							Memory: oamv1alpha2.MemoryResources{Required: kresource.MustParse("64Mi")},
+						},
+					}),
+					cwWithAnnotation(AnnotationKeyContainerOverrides, `{"cool-container":{"limits":{"cpu":"1","memory":"256Mi"}}}`),
+				),
+			},
+			want: want{result: []resource.Object{deployment(dmWithContainer(corev1.Container{
+				Name:  "cool-container",
+				Image: "cool/image:latest",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    kresource.MustParse("500m"),
+						corev1.ResourceMemory: kresource.MustParse("64Mi"),
+					},
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    kresource.MustParse("1"),
+						corev1.ResourceMemory: kresource.MustParse("256Mi"),
+					},
+				},
+			}))}},
+		},
+		"SuccessfulExtendedResourceOverrides": {
+			reason: "Extended resources should be accepted in requests and limits overrides. An extended resource request without a limit should be limited to the same quantity.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+					}),
+					cwWithAnnotation(AnnotationKeyContainerOverrides, `{"cool-container":{"requests":{"nvidia.com/gpu":"1","example.com/widget":"2"},"limits":{"nvidia.com/gpu":"1"}}}`),
+				),
+			},
+			want: want{result: []resource.Object{deployment(dmWithContainer(corev1.Container{
+				Name:  "cool-container",
+				Image: "cool/image:latest",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						"nvidia.com/gpu":     kresource.MustParse("1"),
+						"example.com/widget": kresource.MustParse("2"),
+					},
+					Limits: corev1.ResourceList{
+						"nvidia.com/gpu":     kresource.MustParse("1"),
+						"example.com/widget": kresource.MustParse("2"),
+					},
+				},
+			}))}},
+		},
+		"SuccessfulGPUAndExtendedResources": {
+			reason: "OAM GPU and extended resource requirements should be translated into extended resource requests and limits.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+						Resources: &oamv1alpha2.ContainerResources{
+							CPU:      oamv1alpha2.CPUResources{Required: kresource.MustParse("500m")},
+							Memory:   oamv1alpha2.MemoryResources{Required: kresource.MustParse("64Mi")},
+							GPU:      &oamv1alpha2.GPUResources{Required: kresource.MustParse("1")},
+							Extended: []oamv1alpha2.ExtendedResource{{Name: "example.com/widget", Required: intstr.FromInt(2)}},
+						},
+					}),
+				),
+			},
+			want: want{result: []resource.Object{deployment(dmWithContainer(corev1.Container{
+				Name:  "cool-container",
+				Image: "cool/image:latest",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    kresource.MustParse("500m"),
+						corev1.ResourceMemory: kresource.MustParse("64Mi"),
+						ResourceNameGPU:       kresource.MustParse("1"),
+						"example.com/widget":  kresource.MustParse("2"),
+					},
+					Limits: corev1.ResourceList{
+						ResourceNameGPU:      kresource.MustParse("1"),
+						"example.com/widget": kresource.MustParse("2"),
+					},
+				},
+			}))}},
+		},
+		"ErrorInvalidExtendedResourceName": {
+			reason: "An extended resource in the kubernetes.io domain should return an error.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+						Resources: &oamv1alpha2.ContainerResources{
+							Extended: []oamv1alpha2.ExtendedResource{{Name: "kubernetes.io/widget", Required: intstr.FromInt(1)}},
+						},
+					}),
+				),
+			},
+			want: want{err: errors.Errorf(errFmtNotExtendedResource, "cool-container", "kubernetes.io/widget")},
+		},
+		"SuccessfulEnvironment": {
+			reason: "Literal, secret, and configmap sourced environment variables should be translated.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+						Environment: []oamv1alpha2.ContainerEnvVar{
+							{
+								Name:  "LITERAL",
+								Value: &envVarSecretVal,
+							},
+							{
+								Name: "FROM_SECRET",
+								FromSecret: &oamv1alpha2.SecretKeySelector{
+									Name: "cool-secret",
+									Key:  "secretdata",
+								},
+							},
+						},
+					}),
+					cwWithAnnotation(AnnotationKeyContainerOverrides, `{"cool-container":{"env":[{"name":"FROM_CONFIGMAP","valueFrom":{"configMapKeyRef":{"name":"cool-configmap","key":"configdata"}}}]}}`),
+				),
+			},
+			want: want{result: []resource.Object{deployment(dmWithContainer(corev1.Container{
+				Name:  "cool-container",
+				Image: "cool/image:latest",
+				Env: []corev1.EnvVar{
 					{
-						Name:  "USE_VAL_SECRET",
+						Name:  "LITERAL",
 						Value: envVarSecretVal,
 					},
+					{
+						Name: "FROM_SECRET",
+						ValueFrom: &corev1.EnvVarSource{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								Key:                  "secretdata",
+								LocalObjectReference: corev1.LocalObjectReference{Name: "cool-secret"},
+							},
+						},
+					},
+					{
+						Name: "FROM_CONFIGMAP",
+						ValueFrom: &corev1.EnvVarSource{
+							ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+								Key:                  "configdata",
+								LocalObjectReference: corev1.LocalObjectReference{Name: "cool-configmap"},
+							},
+						},
+					},
 				},
 			}))}},
 		},
-	}
-
-	for name, tc := range cases {
-		t.Run(name, func(t *testing.T) {
-			r, err := Translator(context.Background(), tc.args.w)
-
-			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("\nReason: %s\ncontainerizedWorkloadTranslator(...): -want error, +got error:\n%s", tc.reason, diff)
-			}
-
-			if diff := cmp.Diff(tc.want.result, r); diff != "" {
-				t.Errorf("\nReason: %s\ncontainerizedWorkloadTranslator(...): -want, +got:\n%s", tc.reason, diff)
+		"ErrorAmbiguousEnvVar": {
+			reason: "An environment variable that sets both a value and a secret should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithContainer(oamv1alpha2.Container{
+					Name:  "cool-container",
+					Image: "cool/image:latest",
+					Environment: []oamv1alpha2.ContainerEnvVar{{
+						Name:  "AMBIGUOUS",
+						Value: &envVarSecretVal,
+						FromSecret: &oamv1alpha2.SecretKeySelector{
+							Name: "cool-secret",
+							Key:  "secretdata",
+						},
+					}},
+				})),
+			},
+			want: want{err: errors.Errorf(errFmtAmbiguousEnvVar, "cool-container", "AMBIGUOUS")},
+		},
+		"ErrorAmbiguousOverrideEnvVar": {
+			reason: "An override environment variable that sets both a value and a value source should return an error.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+					}),
+					cwWithAnnotation(AnnotationKeyContainerOverrides, `{"cool-container":{"env":[{"name":"AMBIGUOUS","value":"v","valueFrom":{"configMapKeyRef":{"name":"cool-configmap","key":"configdata"}}}]}}`),
+				),
+			},
+			want: want{err: errors.Errorf(errFmtAmbiguousEnvVar, "cool-container", "AMBIGUOUS")},
+		},
+		"SuccessfulEnvFrom": {
+			reason: "ConfigMap and Secret environment variable sources should be translated, with and without a prefix.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+					}),
+					cwWithAnnotation(AnnotationKeyContainerOverrides, `{"cool-container":{"envFrom":[
+						{"configMapRef":{"name":"cool-configmap"}},
+						{"prefix":"CM_","configMapRef":{"name":"other-configmap"}},
+						{"secretRef":{"name":"cool-secret"}},
+						{"prefix":"SECRET_","secretRef":{"name":"other-secret"}}
+					]}}`),
+				),
+			},
+			want: want{result: []resource.Object{deployment(dmWithContainer(corev1.Container{
+				Name:  "cool-container",
+				Image: "cool/image:latest",
+				EnvFrom: []corev1.EnvFromSource{
+					{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "cool-configmap"}}},
+					{Prefix: "CM_", ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "other-configmap"}}},
+					{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "cool-secret"}}},
+					{Prefix: "SECRET_", SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "other-secret"}}},
+				},
+			}))}},
+		},
+		"ErrorEnvFromNoName": {
+			reason: "An environment variable source that does not name its ConfigMap should return an error.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+					}),
+					cwWithAnnotation(AnnotationKeyContainerOverrides, `{"cool-container":{"envFrom":[{"configMapRef":{}}]}}`),
+				),
+			},
+			want: want{err: errors.Errorf(errFmtEnvFromName, "cool-container", 0, "ConfigMap")},
+		},
+		"ErrorEnvFromAmbiguous": {
+			reason: "An environment variable source that references both a ConfigMap and a Secret should return an error.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+					}),
+					cwWithAnnotation(AnnotationKeyContainerOverrides, `{"cool-container":{"envFrom":[{"configMapRef":{"name":"a"},"secretRef":{"name":"b"}}]}}`),
+				),
+			},
+			want: want{err: errors.Errorf(errFmtEnvFromSource, "cool-container", 0)},
+		},
+		"SuccessfulInitContainers": {
+			reason: "Init containers should be translated in order, before the containers they precede.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+					}),
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cooler-container",
+						Image: "cooler/image:latest",
+					}),
+					cwWithAnnotation(AnnotationKeyInitContainers, `[{"name":"migrate","image":"cool/migrate:latest","command":["migrate"]}]`),
+					cwWithAnnotation(AnnotationKeyContainerOverrides, `{"migrate":{"limits":{"cpu":"1"}}}`),
+				),
+			},
+			want: want{result: []resource.Object{deployment(
+				dmWithInitContainer(corev1.Container{
+					Name:    "migrate",
+					Image:   "cool/migrate:latest",
+					Command: []string{"migrate"},
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{corev1.ResourceCPU: kresource.MustParse("1")},
+					},
+				}),
+				dmWithContainer(corev1.Container{
+					Name:  "cool-container",
+					Image: "cool/image:latest",
+				}),
+				dmWithContainer(corev1.Container{
+					Name:  "cooler-container",
+					Image: "cooler/image:latest",
+				}),
+			)}},
+		},
+		"ErrorInvalidInitContainers": {
+			reason: "An invalid init containers annotation should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyInitContainers, `{`)),
+			},
+			want: want{err: errors.Wrap(errors.New("unexpected end of JSON input"), errParseInitContainers)},
+		},
+		"SuccessfulVolumes": {
+			reason: "Declared volumes of each supported source should be translated and mounted.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+						Resources: &oamv1alpha2.ContainerResources{
+							Volumes: []oamv1alpha2.VolumeResource{
+								{Name: "empty", MouthPath: "/empty"},
+								{Name: "config", MouthPath: "/config"},
+								{Name: "secret", MouthPath: "/secret"},
+								{Name: "data", MouthPath: "/data"},
+							},
+						},
+					}),
+					cwWithAnnotation(AnnotationKeyVolumes, `[
+						{"name":"empty","emptyDir":{}},
+						{"name":"config","configMap":{"name":"cool-configmap"}},
+						{"name":"secret","secret":{"secretName":"cool-secret"}},
+						{"name":"data","persistentVolumeClaim":{"claimName":"cool-claim"}}
+					]`),
+				),
+			},
+			want: want{result: []resource.Object{deployment(
+				dmWithContainer(corev1.Container{
+					Name:  "cool-container",
+					Image: "cool/image:latest",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							"cpu":    {},
+							"memory": {},
+						},
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "empty", MountPath: "/empty"},
+						{Name: "config", MountPath: "/config"},
+						{Name: "secret", MountPath: "/secret"},
+						{Name: "data", MountPath: "/data"},
+					},
+				}),
+				dmWithVolume(corev1.Volume{
+					Name:         "empty",
+					VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+				}),
+				dmWithVolume(corev1.Volume{
+					Name: "config",
+					VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "cool-configmap"},
+					}},
+				}),
+				dmWithVolume(corev1.Volume{
+					Name:         "secret",
+					VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "cool-secret"}},
+				}),
+				dmWithVolume(corev1.Volume{
+					Name:         "data",
+					VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "cool-claim"}},
+				}),
+			)}},
+		},
+		"SuccessfulUndeclaredVolumeMount": {
+			reason: "Volume mounts should not be validated when no volumes are declared using the annotation.",
+			args: args{
+				w: containerizedWorkload(cwWithContainer(oamv1alpha2.Container{
+					Name:  "cool-container",
+					Image: "cool/image:latest",
+					Resources: &oamv1alpha2.ContainerResources{
+						Volumes: []oamv1alpha2.VolumeResource{{Name: "cool-volume", MouthPath: "/cool"}},
+					},
+				})),
+			},
+			want: want{result: []resource.Object{deployment(dmWithContainer(corev1.Container{
+				Name:  "cool-container",
+				Image: "cool/image:latest",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						"cpu":    {},
+						"memory": {},
+					},
+				},
+				VolumeMounts: []corev1.VolumeMount{{Name: "cool-volume", MountPath: "/cool"}},
+			}))}},
+		},
+		"ErrorUndeclaredVolumeMount": {
+			reason: "A volume mount that does not reference a volume declared using the annotation should return an error.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+						Resources: &oamv1alpha2.ContainerResources{
+							Volumes: []oamv1alpha2.VolumeResource{{Name: "missing", MouthPath: "/missing"}},
+						},
+					}),
+					cwWithAnnotation(AnnotationKeyVolumes, `[{"name":"cool-volume","emptyDir":{}}]`),
+				),
+			},
+			want: want{err: errors.Errorf(errFmtUndeclaredVolumeMount, "cool-container", "missing")},
+		},
+		"ErrorUnsupportedVolume": {
+			reason: "A volume with an unsupported source should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyVolumes, `[{"name":"host","hostPath":{"path":"/"}}]`)),
+			},
+			want: want{err: errors.Errorf(errFmtUnsupportedVolume, "host")},
+		},
+		"SuccessfulImagePullSecrets": {
+			reason: "Image pull secrets from containers and the annotation should land on the pod template, deduplicated and without empty names.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:            "cool-container",
+						Image:           "private.example.org/cool/image:latest",
+						ImagePullSecret: &imagePullSecret,
+					}),
+					cwWithAnnotation(AnnotationKeyImagePullSecrets, "other-secret, ,cool-secret,,other-secret"),
+				),
+			},
+			want: want{result: []resource.Object{deployment(
+				dmWithContainer(corev1.Container{
+					Name:  "cool-container",
+					Image: "private.example.org/cool/image:latest",
+				}),
+				dmWithImagePullSecret("cool-secret"),
+				dmWithImagePullSecret("other-secret"),
+			)}},
+		},
+		"SuccessfulCommandOnly": {
+			reason: "A container command should override the image entrypoint.",
+			args: args{
+				w: containerizedWorkload(cwWithContainer(oamv1alpha2.Container{
+					Name:    "cool-container",
+					Image:   "cool/image:latest",
+					Command: []string{"run"},
+				})),
+			},
+			want: want{result: []resource.Object{deployment(dmWithContainer(corev1.Container{
+				Name:    "cool-container",
+				Image:   "cool/image:latest",
+				Command: []string{"run"},
+			}))}},
+		},
+		"SuccessfulArgsOnly": {
+			reason: "Container arguments should be passed to the image entrypoint.",
+			args: args{
+				w: containerizedWorkload(cwWithContainer(oamv1alpha2.Container{
+					Name:      "cool-container",
+					Image:     "cool/image:latest",
+					Arguments: []string{"--coolflag"},
+				})),
+			},
+			want: want{result: []resource.Object{deployment(dmWithContainer(corev1.Container{
+				Name:  "cool-container",
+				Image: "cool/image:latest",
+				Args:  []string{"--coolflag"},
+			}))}},
+		},
+		"SuccessfulCommandAndArgs": {
+			reason: "A container command and arguments should both be translated.",
+			args: args{
+				w: containerizedWorkload(cwWithContainer(oamv1alpha2.Container{
+					Name:      "cool-container",
+					Image:     "cool/image:latest",
+					Command:   []string{"run"},
+					Arguments: []string{"--coolflag"},
+				})),
+			},
+			want: want{result: []resource.Object{deployment(dmWithContainer(corev1.Container{
+				Name:    "cool-container",
+				Image:   "cool/image:latest",
+				Command: []string{"run"},
+				Args:    []string{"--coolflag"},
+			}))}},
+		},
+		"SuccessfulEmptyCommand": {
+			reason: "An explicitly empty command should be preserved rather than omitted.",
+			args: args{
+				w: containerizedWorkload(cwWithContainer(oamv1alpha2.Container{
+					Name:    "cool-container",
+					Image:   "cool/image:latest",
+					Command: []string{},
+				})),
+			},
+			want: want{result: []resource.Object{deployment(dmWithContainer(corev1.Container{
+				Name:    "cool-container",
+				Image:   "cool/image:latest",
+				Command: []string{},
+			}))}},
+		},
+		"SuccessfulReplicas": {
+			reason: "The replicas annotation should set the Deployment's desired replicas.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyReplicas, "3")),
+			},
+			want: want{result: []resource.Object{deployment(dmWithReplicas(3))}},
+		},
+		"SuccessfulZeroReplicas": {
+			reason: "A replicas annotation of zero should be respected rather than defaulted.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyReplicas, "0")),
+			},
+			want: want{result: []resource.Object{deployment(dmWithReplicas(0))}},
+		},
+		"ErrorNegativeReplicas": {
+			reason: "A negative replicas annotation should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyReplicas, "-1")),
+			},
+			want: want{err: errors.Errorf(errFmtNegativeReplicas, -1)},
+		},
+		"ErrorInvalidReplicas": {
+			reason: "A replicas annotation that is not an integer should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyReplicas, "many")),
+			},
+			want: want{err: errors.Wrap(errors.New(`strconv.ParseInt: parsing "many": invalid syntax`), errParseReplicas)},
+		},
+		"SuccessfulContainerSecurityContext": {
+			reason: "A container security context should be translated, preserving explicitly false fields.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+					}),
+					cwWithAnnotation(AnnotationKeyContainerOverrides, `{"cool-container":{"securityContext":{
+						"runAsNonRoot": false,
+						"readOnlyRootFilesystem": true,
+						"capabilities": {"drop": ["ALL"]}
+					}}}`),
+				),
+			},
+			want: want{result: []resource.Object{deployment(dmWithContainer(corev1.Container{
+				Name:  "cool-container",
+				Image: "cool/image:latest",
+				SecurityContext: &corev1.SecurityContext{
+					RunAsNonRoot:           &no,
+					ReadOnlyRootFilesystem: &yes,
+					Capabilities:           &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+				},
+			}))}},
+		},
+		"SuccessfulInteractive": {
+			reason: "The stdin, stdinOnce, and tty flags of a container should be translated.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+					}),
+					cwWithAnnotation(AnnotationKeyContainerOverrides, `{"cool-container":{"stdin":true,"stdinOnce":true,"tty":true}}`),
+				),
+			},
+			want: want{result: []resource.Object{deployment(dmWithContainer(corev1.Container{
+				Name:      "cool-container",
+				Image:     "cool/image:latest",
+				Stdin:     true,
+				StdinOnce: true,
+				TTY:       true,
+			}))}},
+		},
+		"SuccessfulLifecycle": {
+			reason: "Container lifecycle hooks should be translated.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+					}),
+					cwWithAnnotation(AnnotationKeyContainerOverrides, `{"cool-container":{"lifecycle":{"preStop":{"exec":{"command":["sleep","5"]}}}}}`),
+				),
+			},
+			want: want{result: []resource.Object{deployment(dmWithContainer(corev1.Container{
+				Name:  "cool-container",
+				Image: "cool/image:latest",
+				Lifecycle: &corev1.Lifecycle{
+					PreStop: &corev1.Handler{Exec: &corev1.ExecAction{Command: []string{"sleep", "5"}}},
+				},
+			}))}},
+		},
+		"ErrorLifecycleNoHandler": {
+			reason: "A lifecycle hook without a handler should return an error.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+					}),
+					cwWithAnnotation(AnnotationKeyContainerOverrides, `{"cool-container":{"lifecycle":{"preStop":{}}}}`),
+				),
+			},
+			want: want{err: errors.Errorf(errFmtNoLifecycleHandler, "cool-container", hookPreStop)},
+		},
+		"SuccessfulRecreateStrategy": {
+			reason: "A Recreate update strategy should be set on the Deployment.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyUpdateStrategy, `{"type":"Recreate"}`)),
+			},
+			want: want{result: []resource.Object{deployment(dmWithStrategy(appsv1.DeploymentStrategy{
+				Type: appsv1.RecreateDeploymentStrategyType,
+			}))}},
+		},
+		"SuccessfulRollingUpdateStrategy": {
+			reason: "A tuned rolling update strategy should be set on the Deployment, defaulting its type.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyUpdateStrategy, `{"rollingUpdate":{"maxSurge":"50%","maxUnavailable":0}}`)),
+			},
+			want: want{result: []resource.Object{deployment(dmWithStrategy(appsv1.DeploymentStrategy{
+				Type: appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{
+					MaxSurge:       &maxSurge,
+					MaxUnavailable: &maxUnavailable,
+				},
+			}))}},
+		},
+		"ErrorRecreateWithRollingUpdate": {
+			reason: "A Recreate update strategy that tunes a rolling update should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyUpdateStrategy, `{"type":"Recreate","rollingUpdate":{"maxSurge":1}}`)),
+			},
+			want: want{err: errors.New(errRecreateWithRollingUpdate)},
+		},
+		"ErrorInvalidUpdateStrategy": {
+			reason: "An unknown update strategy type should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyUpdateStrategy, `{"type":"BlueGreen"}`)),
+			},
+			want: want{err: errors.Errorf(errFmtInvalidUpdateStrategy, "BlueGreen")},
+		},
+		"SuccessfulRevisionHistoryLimit": {
+			reason: "A revision history limit should be set on the Deployment.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyRevisionHistoryLimit, "3")),
+			},
+			want: want{result: []resource.Object{deployment(dmWithRevisionHistoryLimit(3))}},
+		},
+		"SuccessfulZeroRevisionHistoryLimit": {
+			reason: "A revision history limit of zero should be honoured, keeping no history.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyRevisionHistoryLimit, "0")),
+			},
+			want: want{result: []resource.Object{deployment(dmWithRevisionHistoryLimit(0))}},
+		},
+		"ErrorNegativeRevisionHistoryLimit": {
+			reason: "A negative revision history limit should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyRevisionHistoryLimit, "-1")),
+			},
+			want: want{err: errors.Errorf(errFmtNegativeRevisionLimit, -1)},
+		},
+		"SuccessfulMinReadySeconds": {
+			reason: "Min ready seconds should be set on the Deployment alongside the readiness probe it delays availability after.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+						ReadinessProbe: &oamv1alpha2.ContainerHealthProbe{
+							HTTPGet: &oamv1alpha2.HTTPGetProbe{Path: "/ready", Port: 8080},
+						},
+					}),
+					cwWithAnnotation(AnnotationKeyMinReadySeconds, "15"),
+				),
+			},
+			want: want{result: []resource.Object{deployment(
+				dmWithMinReadySeconds(15),
+				dmWithContainer(corev1.Container{
+					Name:  "cool-container",
+					Image: "cool/image:latest",
+					ReadinessProbe: &corev1.Probe{
+						Handler: corev1.Handler{
+							HTTPGet: &corev1.HTTPGetAction{Path: "/ready", Port: intstr.FromInt(8080)},
+						},
+						PeriodSeconds:    defaultProbePeriodSeconds,
+						TimeoutSeconds:   defaultProbeTimeoutSeconds,
+						SuccessThreshold: defaultProbeSuccessThreshold,
+						FailureThreshold: defaultProbeFailureThreshold,
+					},
+				}),
+			)}},
+		},
+		"SuccessfulProgressDeadline": {
+			reason: "A progress deadline greater than the min ready seconds should be set on the Deployment.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithAnnotation(AnnotationKeyMinReadySeconds, "10"),
+					cwWithAnnotation(AnnotationKeyProgressDeadline, "120"),
+				),
+			},
+			want: want{result: []resource.Object{deployment(dmWithProgressDeadline(10, 120))}},
+		},
+		"ErrorProgressDeadlineNotAfterMinReady": {
+			reason: "A progress deadline that is not greater than the min ready seconds should return an error.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithAnnotation(AnnotationKeyMinReadySeconds, "60"),
+					cwWithAnnotation(AnnotationKeyProgressDeadline, "60"),
+				),
+			},
+			want: want{err: errors.Errorf(errFmtProgressDeadline, 60, 60)},
+		},
+		"ErrorNegativeMinReadySeconds": {
+			reason: "A negative min ready seconds should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyMinReadySeconds, "-1")),
+			},
+			want: want{err: errors.Errorf(errFmtNegativeMinReady, -1)},
+		},
+		"SuccessfulStartupProbe": {
+			reason: "A startup probe supplied by container overrides should be translated.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+					}),
+					cwWithAnnotation(AnnotationKeyContainerOverrides, `{"cool-container":{"startupProbe":{"tcpSocket":{"port":5432}}}}`),
+				),
+			},
+			want: want{result: []resource.Object{deployment(dmWithContainer(corev1.Container{
+				Name:  "cool-container",
+				Image: "cool/image:latest",
+				StartupProbe: &corev1.Probe{
+					Handler: corev1.Handler{
+						TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(5432)},
+					},
+					PeriodSeconds:    defaultProbePeriodSeconds,
+					TimeoutSeconds:   defaultProbeTimeoutSeconds,
+					SuccessThreshold: defaultProbeSuccessThreshold,
+					FailureThreshold: defaultProbeFailureThreshold,
+				},
+			}))}},
+		},
+		"SuccessfulPodSecurityContext": {
+			reason: "A pod security context should be translated, leaving omitted fields unset.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithAnnotation(AnnotationKeyPodSecurityContext, `{"fsGroup": 2000}`),
+				),
+			},
+			want: want{result: []resource.Object{deployment(dmWithPodSecurityContext(&corev1.PodSecurityContext{
+				FSGroup: &fsGroup,
+			}))}},
+		},
+		"ErrorInvalidPodSecurityContext": {
+			reason: "An invalid pod security context annotation should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyPodSecurityContext, `{`)),
+			},
+			want: want{err: errors.Wrap(errors.New("unexpected end of JSON input"), errParseSecurityContext)},
+		},
+		"SuccessfulTerminationGracePeriod": {
+			reason: "The termination grace period annotation should set the pod's termination grace period.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyTerminationGracePeriod, "120")),
+			},
+			want: want{result: []resource.Object{deployment(dmWithTerminationGracePeriod(120))}},
+		},
+		"SuccessfulZeroTerminationGracePeriod": {
+			reason: "A termination grace period of zero should be passed through rather than treated as unset.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyTerminationGracePeriod, "0")),
+			},
+			want: want{result: []resource.Object{deployment(dmWithTerminationGracePeriod(0))}},
+		},
+		"ErrorNegativeTerminationGracePeriod": {
+			reason: "A negative termination grace period should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyTerminationGracePeriod, "-5")),
+			},
+			want: want{err: errors.Errorf(errFmtNegativeGracePeriod, -5)},
+		},
+		"SuccessfulJob": {
+			reason: "A ContainerizedWorkload that runs to completion should be translated into a Job carrying its containers.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/migrate:latest",
+						Environment: []oamv1alpha2.ContainerEnvVar{
+							{Name: "LITERAL", Value: &envVarSecretVal},
+						},
+						Resources: &oamv1alpha2.ContainerResources{
+							CPU:    oamv1alpha2.CPUResources{Required: kresource.MustParse("500m")},
+							Memory: oamv1alpha2.MemoryResources{Required: kresource.MustParse("64Mi")},
+						},
+					}),
+					cwWithAnnotation(AnnotationKeyRestartPolicy, string(corev1.RestartPolicyOnFailure)),
+				),
+			},
+			want: want{result: []resource.Object{&batchv1.Job{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Job",
+					APIVersion: "batch/v1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: cwName,
+				},
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{
+								SelectorLabelKey:     cwUID,
+								oamworkload.LabelKey: cwUID,
+							},
+						},
+						Spec: corev1.PodSpec{
+							RestartPolicy: corev1.RestartPolicyOnFailure,
+							Containers: []corev1.Container{{
+								Name:  "cool-container",
+								Image: "cool/migrate:latest",
+								Env:   []corev1.EnvVar{{Name: "LITERAL", Value: envVarSecretVal}},
+								Resources: corev1.ResourceRequirements{
+									Requests: corev1.ResourceList{
+										corev1.ResourceCPU:    kresource.MustParse("500m"),
+										corev1.ResourceMemory: kresource.MustParse("64Mi"),
+									},
+								},
+							}},
+						},
+					},
+				},
+			}}},
+		},
+		"SuccessfulDaemonSet": {
+			reason: "A workload that sets the daemon set annotation should be translated into a DaemonSet without replicas.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/agent:v1.0.0",
+					}),
+					cwWithAnnotation(AnnotationKeyDaemonSet, "true"),
+				),
+			},
+			want: want{result: []resource.Object{&appsv1.DaemonSet{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "DaemonSet",
+					APIVersion: "apps/v1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: cwName,
+				},
+				Spec: appsv1.DaemonSetSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							SelectorLabelKey: cwUID,
+						},
+					},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{
+								SelectorLabelKey:     cwUID,
+								oamworkload.LabelKey: cwUID,
+							},
+						},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{
+								Name:  "cool-container",
+								Image: "cool/agent:v1.0.0",
+							}},
+						},
+					},
+				},
+			}}},
+		},
+		"ErrorDaemonSetReplicas": {
+			reason: "A daemon set workload that also specifies replicas should return an error.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithAnnotation(AnnotationKeyDaemonSet, "true"),
+					cwWithAnnotation(AnnotationKeyReplicas, "3"),
+				),
+			},
+			want: want{err: errors.New(errDaemonSetReplicas)},
+		},
+		"ErrorDaemonSetRestartPolicy": {
+			reason: "A daemon set workload that runs to completion should return an error.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithAnnotation(AnnotationKeyDaemonSet, "true"),
+					cwWithAnnotation(AnnotationKeyRestartPolicy, string(corev1.RestartPolicyNever)),
+				),
+			},
+			want: want{err: errors.New(errDaemonSetRestartPolicy)},
+		},
+		"ErrorInvalidDaemonSet": {
+			reason: "An invalid daemon set annotation should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyDaemonSet, "sometimes")),
+			},
+			want: want{err: errors.Wrap(errors.New(`strconv.ParseBool: parsing "sometimes": invalid syntax`), errParseDaemonSet)},
+		},
+		"SuccessfulConfigFile": {
+			reason: "An inline config file should be stored in a ConfigMap that is mounted at the file's path.",
+			args: args{
+				w: containerizedWorkload(cwWithContainer(oamv1alpha2.Container{
+					Name:        "cool-container",
+					Image:       "cool/image:latest",
+					ConfigFiles: []oamv1alpha2.ContainerConfigFile{{Path: "/etc/cool.conf", Value: &configVal}},
+				})),
+			},
+			want: want{result: []resource.Object{
+				deployment(
+					dmWithContainer(corev1.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: ConfigVolumeName, MountPath: "/etc/cool.conf", SubPath: "cool-container_etc_cool.conf", ReadOnly: true},
+						},
+					}),
+					dmWithVolume(configMapVolume),
+				),
+				configMap(map[string]string{"cool-container_etc_cool.conf": configVal}),
+			}},
+		},
+		"SuccessfulConfigFiles": {
+			reason: "Inline config files of several containers should be stored in one ConfigMap, and each mounted into its own container. Config files sourced from a secret should be mounted from that secret.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+						ConfigFiles: []oamv1alpha2.ContainerConfigFile{
+							{Path: "/etc/cool.conf", Value: &configVal},
+							{Path: "/etc/cool/extra.yaml", Value: &configVal},
+							{Path: "/etc/cool.secret", FromSecret: &oamv1alpha2.SecretKeySelector{Name: "cool", Key: "secret"}},
+						},
+					}),
+					cwWithContainer(oamv1alpha2.Container{
+						Name:        "sidecar",
+						Image:       "cool/sidecar:latest",
+						ConfigFiles: []oamv1alpha2.ContainerConfigFile{{Path: "/etc/cool.conf", Value: &configVal}},
+					}),
+				),
+			},
+			want: want{result: []resource.Object{
+				deployment(
+					dmWithContainer(corev1.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: ConfigVolumeName, MountPath: "/etc/cool.conf", SubPath: "cool-container_etc_cool.conf", ReadOnly: true},
+							{Name: ConfigVolumeName, MountPath: "/etc/cool/extra.yaml", SubPath: "cool-container_etc_cool_extra.yaml", ReadOnly: true},
+							{Name: SecretVolumeNamePrefix + "0", MountPath: "/etc/cool.secret", SubPath: "secret", ReadOnly: true},
+						},
+					}),
+					dmWithContainer(corev1.Container{
+						Name:  "sidecar",
+						Image: "cool/sidecar:latest",
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: ConfigVolumeName, MountPath: "/etc/cool.conf", SubPath: "sidecar_etc_cool.conf", ReadOnly: true},
+						},
+					}),
+					dmWithVolume(configMapVolume),
+					dmWithVolume(corev1.Volume{
+						Name: SecretVolumeNamePrefix + "0",
+						VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{SecretName: "cool"},
+						},
+					}),
+				),
+				configMap(map[string]string{
+					"cool-container_etc_cool.conf":       configVal,
+					"cool-container_etc_cool_extra.yaml": configVal,
+					"sidecar_etc_cool.conf":              configVal,
+				}),
+			}},
+		},
+		"SuccessfulSecretDataEnv": {
+			reason: "Secret data should be stored in a Secret that environment variables may reference.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+						Environment: []oamv1alpha2.ContainerEnvVar{{
+							Name:       "PASSWORD",
+							FromSecret: &oamv1alpha2.SecretKeySelector{Name: cwName + SecretNameSuffix, Key: "password"},
+						}},
+					}),
+					cwWithAnnotation(AnnotationKeySecretData, `{"password":"hunter2"}`),
+				),
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithContainer(corev1.Container{
+					Name:  "cool-container",
+					Image: "cool/image:latest",
+					Env: []corev1.EnvVar{{
+						Name: "PASSWORD",
+						ValueFrom: &corev1.EnvVarSource{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: cwName + SecretNameSuffix},
+								Key:                  "password",
+							},
+						},
+					}},
+				})),
+				secret(map[string][]byte{"password": []byte("hunter2")}),
+			}},
+		},
+		"SuccessfulSecretDataMount": {
+			reason: "Secret data should be stored in a Secret from which config files may be mounted.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+						ConfigFiles: []oamv1alpha2.ContainerConfigFile{{
+							Path:       "/etc/cool/tls.key",
+							FromSecret: &oamv1alpha2.SecretKeySelector{Name: cwName + SecretNameSuffix, Key: "tls.key"},
+						}},
+					}),
+					cwWithAnnotation(AnnotationKeySecretData, `{"tls.key":"very-secret"}`),
+				),
+			},
+			want: want{result: []resource.Object{
+				deployment(
+					dmWithContainer(corev1.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: SecretVolumeNamePrefix + "0", MountPath: "/etc/cool/tls.key", SubPath: "tls.key", ReadOnly: true},
+						},
+					}),
+					dmWithVolume(corev1.Volume{
+						Name: SecretVolumeNamePrefix + "0",
+						VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{SecretName: cwName + SecretNameSuffix},
+						},
+					}),
+				),
+				secret(map[string][]byte{"tls.key": []byte("very-secret")}),
+			}},
+		},
+		"ErrorInvalidSecretData": {
+			reason: "An invalid secret data annotation should return an error that does not include the data.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeySecretData, `{"password":hunter2}`)),
+			},
+			want: want{err: errors.New(errParseSecretData)},
+		},
+		"ErrorConflictingConfigFiles": {
+			reason: "Config files of the same container whose paths map to the same ConfigMap key should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithContainer(oamv1alpha2.Container{
+					Name:  "cool-container",
+					Image: "cool/image:latest",
+					ConfigFiles: []oamv1alpha2.ContainerConfigFile{
+						{Path: "/etc/cool/a", Value: &configVal},
+						{Path: "/etc/cool_a", Value: &configVal},
+					},
+				})),
+			},
+			want: want{err: errors.Errorf(errFmtDuplicateConfigFile, "cool-container", "/etc/cool_a")},
+		},
+		"ErrorReservedVolume": {
+			reason: "A declared volume that uses the name reserved for config files should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyVolumes, `[{"name":"`+ConfigVolumeName+`","emptyDir":{}}]`)),
+			},
+			want: want{err: errors.Errorf(errFmtReservedVolume, ConfigVolumeName)},
+		},
+		"ErrorEmptyImage": {
+			reason: "A container without an image should return an error naming the container.",
+			args: args{
+				w: containerizedWorkload(cwWithContainer(oamv1alpha2.Container{Name: "cool-container"})),
+			},
+			want: want{err: errors.Errorf(errFmtEmptyImage, "cool-container")},
+		},
+		"ErrorInvalidImage": {
+			reason: "A container whose image is not a valid image reference should return an error naming the container.",
+			args: args{
+				w: containerizedWorkload(cwWithContainer(oamv1alpha2.Container{
+					Name:  "cool-container",
+					Image: "cool/image:not valid",
+				})),
+			},
+			want: want{err: errors.Wrapf(reference.ErrReferenceInvalidFormat, errFmtInvalidImage, "cool-container", "cool/image:not valid")},
+		},
+		"SuccessfulHostAliases": {
+			reason: "Host aliases should be added to the pod template, omitting exact duplicates.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyHostAliases, `[
+					{"ip":"10.0.0.1","hostnames":["cool.example.org","db.example.org"]},
+					{"ip":"fd00::1","hostnames":["cool6.example.org"]},
+					{"ip":"10.0.0.1","hostnames":["db.example.org","cool.example.org"]}
+				]`)),
+			},
+			want: want{result: []resource.Object{deployment(dmWithHostAliases(
+				corev1.HostAlias{IP: "10.0.0.1", Hostnames: []string{"cool.example.org", "db.example.org"}},
+				corev1.HostAlias{IP: "fd00::1", Hostnames: []string{"cool6.example.org"}},
+			))}},
+		},
+		"ErrorInvalidHostAliasIP": {
+			reason: "A host alias with an invalid IP address should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyHostAliases, `[{"ip":"10.0.0.300","hostnames":["cool.example.org"]}]`)),
+			},
+			want: want{err: errors.Errorf(errFmtInvalidHostAliasIP, "10.0.0.300")},
+		},
+		"ErrorConflictingHostAlias": {
+			reason: "Host aliases that map the same IP address to different hostnames should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyHostAliases, `[
+					{"ip":"10.0.0.1","hostnames":["cool.example.org"]},
+					{"ip":"10.0.0.1","hostnames":["db.example.org"]}
+				]`)),
+			},
+			want: want{err: errors.Errorf(errFmtConflictingHostAlias, "10.0.0.1")},
+		},
+		"SuccessfulPriorityClassName": {
+			reason: "Pods should be assigned the priority class named by the workload.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyPriorityClassName, "high-priority")),
+			},
+			want: want{result: []resource.Object{deployment(dmWithPriorityClassName("high-priority"))}},
+		},
+		"ErrorInvalidPriorityClassName": {
+			reason: "A priority class name that is not a valid DNS subdomain should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyPriorityClassName, "High_Priority")),
+			},
+			want: want{err: errors.Errorf(errFmtInvalidPriorityClass, "High_Priority",
+				strings.Join(validation.IsDNS1123Subdomain("High_Priority"), ", "))},
+		},
+		"SuccessfulReadinessGates": {
+			reason: "Pods should have the readiness gates requested by the workload.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyReadinessGates, "target-health.elbv2.k8s.aws/cool-tg, example.org/ready")),
+			},
+			want: want{result: []resource.Object{deployment(dmWithReadinessGates("target-health.elbv2.k8s.aws/cool-tg", "example.org/ready"))}},
+		},
+		"ErrorEmptyReadinessGate": {
+			reason: "An empty readiness gate condition type should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyReadinessGates, "example.org/ready,")),
+			},
+			want: want{err: errors.New(errEmptyReadinessGate)},
+		},
+		"ErrorInvalidReadinessGate": {
+			reason: "A readiness gate condition type that is not a qualified name should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyReadinessGates, "not ready")),
+			},
+			want: want{err: errors.Errorf(errFmtInvalidReadinessGate, "not ready",
+				strings.Join(validation.IsQualifiedName("not ready"), ", "))},
+		},
+		"ErrorDuplicateReadinessGate": {
+			reason: "A readiness gate condition type that is specified twice should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyReadinessGates, "example.org/ready,example.org/ready")),
+			},
+			want: want{err: errors.Errorf(errFmtDuplicateReadinessGate, "example.org/ready")},
+		},
+		"SuccessfulDNSConfig": {
+			reason: "Pods should use the DNS policy and nameservers requested by the workload.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithAnnotation(AnnotationKeyDNSPolicy, string(corev1.DNSNone)),
+					cwWithAnnotation(AnnotationKeyDNSConfig, `{"nameservers":["10.0.0.10","fd00::10"],"searches":["cool.svc.example.org"]}`),
+				),
+			},
+			want: want{result: []resource.Object{deployment(dmWithDNS(corev1.DNSNone, &corev1.PodDNSConfig{
+				Nameservers: []string{"10.0.0.10", "fd00::10"},
+				Searches:    []string{"cool.svc.example.org"},
+			}))}},
+		},
+		"SuccessfulDNSPolicy": {
+			reason: "Pods should use the DNS policy requested by the workload.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyDNSPolicy, string(corev1.DNSClusterFirstWithHostNet))),
+			},
+			want: want{result: []resource.Object{deployment(dmWithDNS(corev1.DNSClusterFirstWithHostNet, nil))}},
+		},
+		"ErrorInvalidDNSPolicy": {
+			reason: "An unknown DNS policy should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyDNSPolicy, "Cool")),
+			},
+			want: want{err: errors.Errorf(errFmtInvalidDNSPolicy, "Cool")},
+		},
+		"ErrorDNSPolicyNoneWithoutConfig": {
+			reason: "The None DNS policy without a DNS config should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyDNSPolicy, string(corev1.DNSNone))),
+			},
+			want: want{err: errors.New(errDNSPolicyNoneNoServers)},
+		},
+		"ErrorDNSPolicyNoneWithoutNameservers": {
+			reason: "The None DNS policy with a DNS config that specifies no nameservers should return an error.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithAnnotation(AnnotationKeyDNSPolicy, string(corev1.DNSNone)),
+					cwWithAnnotation(AnnotationKeyDNSConfig, `{"searches":["cool.svc.example.org"]}`),
+				),
+			},
+			want: want{err: errors.New(errDNSPolicyNoneNoServers)},
+		},
+		"ErrorTooManyNameservers": {
+			reason: "A DNS config with more nameservers than Kubernetes permits should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyDNSConfig, `{"nameservers":["10.0.0.1","10.0.0.2","10.0.0.3","10.0.0.4"]}`)),
+			},
+			want: want{err: errors.Errorf(errFmtTooManyNameservers, maxDNSNameservers, 4)},
+		},
+		"ErrorInvalidNameserver": {
+			reason: "A nameserver that is not an IP address should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyDNSConfig, `{"nameservers":["dns.example.org"]}`)),
+			},
+			want: want{err: errors.Errorf(errFmtInvalidNameserver, "dns.example.org")},
+		},
+		"SuccessfulHostNetwork": {
+			reason: "Pods should use the host's network when requested, exposing container ports as host ports.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+						Ports: []oamv1alpha2.ContainerPort{{Name: "http", Port: 8080}},
+					}),
+					cwWithAnnotation(AnnotationKeyHostNetwork, "true"),
+				),
+			},
+			want: want{result: []resource.Object{deployment(
+				dmWithContainer(corev1.Container{
+					Name:  "cool-container",
+					Image: "cool/image:latest",
+					Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080, HostPort: 8080}},
+				}),
+				dmWithHostNamespaces(true, false, false),
+			)}},
+		},
+		"SuccessfulHostPID": {
+			reason: "Pods should use the host's process ID namespace when requested.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyHostPID, "true")),
+			},
+			want: want{result: []resource.Object{deployment(dmWithHostNamespaces(false, true, false))}},
+		},
+		"SuccessfulHostIPC": {
+			reason: "Pods should use the host's IPC namespace when requested.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyHostIPC, "true")),
+			},
+			want: want{result: []resource.Object{deployment(dmWithHostNamespaces(false, false, true))}},
+		},
+		"ErrorParseHostNetwork": {
+			reason: "A host network annotation that is not a boolean should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyHostNetwork, "sometimes")),
+			},
+			want: want{err: errors.Wrap(errors.New(`strconv.ParseBool: parsing "sometimes": invalid syntax`), errParseHostNetwork)},
+		},
+		"SuccessfulServiceAccount": {
+			reason: "Pods should run as an existing service account named by the workload.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyServiceAccount, "cool-sa")),
+			},
+			want: want{result: []resource.Object{deployment(dmWithServiceAccount("cool-sa"))}},
+		},
+		"SuccessfulCreateServiceAccount": {
+			reason: "A ServiceAccount should be created for pods to run as if the workload requests it.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithAnnotation(AnnotationKeyServiceAccount, "cool-sa"),
+					cwWithAnnotation(AnnotationKeyCreateServiceAccount, "true"),
+				),
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithServiceAccount("cool-sa")),
+				&corev1.ServiceAccount{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "ServiceAccount",
+						APIVersion: "v1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cool-sa",
+						Labels: map[string]string{
+							oamworkload.LabelKey: cwUID,
+						},
+					},
+				},
+			}},
+		},
+		"ErrorCreateServiceAccountWithoutName": {
+			reason: "Requesting a ServiceAccount be created without naming it should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyCreateServiceAccount, "true")),
+			},
+			want: want{err: errors.New(errCreateServiceAccount)},
+		},
+		"ErrorInvalidRestartPolicy": {
+			reason: "An unknown restart policy should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyRestartPolicy, "Sometimes")),
+			},
+			want: want{err: errors.Errorf(errFmtInvalidRestartPolicy, "Sometimes")},
+		},
+		"ErrorInvalidQuantity": {
+			reason: "An invalid resource quantity should return an error naming the container and field.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(oamv1alpha2.Container{
+						Name:  "cool-container",
+						Image: "cool/image:latest",
+					}),
+					cwWithAnnotation(AnnotationKeyContainerOverrides, `{"cool-container":{"limits":{"cpu":"lots"}}}`),
+				),
+			},
+			want: want{err: errors.Wrapf(kresource.ErrFormatWrong, errFmtInvalidQuantity, "cool-container", "limits", "lots", "cpu")},
+		},
+		"ErrorInvalidOverrides": {
+			reason: "An invalid container overrides annotation should return an error.",
+			args: args{
+				w: containerizedWorkload(cwWithAnnotation(AnnotationKeyContainerOverrides, `{`)),
+			},
+			want: want{err: errors.Wrap(errors.New("unexpected end of JSON input"), errParseContainerOverrides)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := Translator(context.Background(), tc.args.w)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\ncontainerizedWorkloadTranslator(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, r); diff != "" {
+				t.Errorf("\nReason: %s\ncontainerizedWorkloadTranslator(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestProbe(t *testing.T) {
+	delay := int32(5)
+	period := int32(30)
+
+	cases := map[string]struct {
+		reason string
+		p      *oamv1alpha2.ContainerHealthProbe
+		want   *corev1.Probe
+	}{
+		"NoProbe": {
+			reason: "A nil OAM probe should produce a nil Kubernetes probe.",
+			p:      nil,
+			want:   nil,
+		},
+		"HTTPGet": {
+			reason: "An HTTP probe should be translated, with unset timings defaulted.",
+			p: &oamv1alpha2.ContainerHealthProbe{
+				HTTPGet: &oamv1alpha2.HTTPGetProbe{
+					Path:        "/healthz",
+					Port:        8080,
+					HTTPHeaders: []oamv1alpha2.HTTPHeader{{Name: "X-Cool", Value: "very"}},
+				},
+			},
+			want: &corev1.Probe{
+				Handler: corev1.Handler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Path:        "/healthz",
+						Port:        intstr.FromInt(8080),
+						HTTPHeaders: []corev1.HTTPHeader{{Name: "X-Cool", Value: "very"}},
+					},
+				},
+				PeriodSeconds:    defaultProbePeriodSeconds,
+				TimeoutSeconds:   defaultProbeTimeoutSeconds,
+				SuccessThreshold: defaultProbeSuccessThreshold,
+				FailureThreshold: defaultProbeFailureThreshold,
+			},
+		},
+		"TCPSocket": {
+			reason: "A TCP probe should be translated, with explicit timings preserved.",
+			p: &oamv1alpha2.ContainerHealthProbe{
+				TCPSocket:           &oamv1alpha2.TCPSocketProbe{Port: 5432},
+				InitialDelaySeconds: &delay,
+				PeriodSeconds:       &period,
+			},
+			want: &corev1.Probe{
+				Handler: corev1.Handler{
+					TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(5432)},
+				},
+				InitialDelaySeconds: delay,
+				PeriodSeconds:       period,
+				TimeoutSeconds:      defaultProbeTimeoutSeconds,
+				SuccessThreshold:    defaultProbeSuccessThreshold,
+				FailureThreshold:    defaultProbeFailureThreshold,
+			},
+		},
+		"Exec": {
+			reason: "An exec probe should be translated, with unset timings defaulted.",
+			p: &oamv1alpha2.ContainerHealthProbe{
+				Exec: &oamv1alpha2.ExecProbe{Command: []string{"cool", "--check"}},
+			},
+			want: &corev1.Probe{
+				Handler: corev1.Handler{
+					Exec: &corev1.ExecAction{Command: []string{"cool", "--check"}},
+				},
+				PeriodSeconds:    defaultProbePeriodSeconds,
+				TimeoutSeconds:   defaultProbeTimeoutSeconds,
+				SuccessThreshold: defaultProbeSuccessThreshold,
+				FailureThreshold: defaultProbeFailureThreshold,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := probe(tc.p)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nprobe(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	type want struct {
+		l   *corev1.Lifecycle
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		l      *ContainerLifecycle
+		want   want
+	}{
+		"NoLifecycle": {
+			reason: "A nil lifecycle should produce a nil Kubernetes lifecycle.",
+		},
+		"PreStopExec": {
+			reason: "An exec preStop hook should be translated.",
+			l: &ContainerLifecycle{
+				PreStop: &LifecycleHandler{Exec: &oamv1alpha2.ExecProbe{Command: []string{"sleep", "5"}}},
+			},
+			want: want{l: &corev1.Lifecycle{
+				PreStop: &corev1.Handler{Exec: &corev1.ExecAction{Command: []string{"sleep", "5"}}},
+			}},
+		},
+		"PostStartHTTPGet": {
+			reason: "An HTTP postStart hook should be translated.",
+			l: &ContainerLifecycle{
+				PostStart: &LifecycleHandler{HTTPGet: &oamv1alpha2.HTTPGetProbe{
+					Path:        "/warm",
+					Port:        8080,
+					HTTPHeaders: []oamv1alpha2.HTTPHeader{{Name: "X-Cool", Value: "very"}},
+				}},
+			},
+			want: want{l: &corev1.Lifecycle{
+				PostStart: &corev1.Handler{HTTPGet: &corev1.HTTPGetAction{
+					Path:        "/warm",
+					Port:        intstr.FromInt(8080),
+					HTTPHeaders: []corev1.HTTPHeader{{Name: "X-Cool", Value: "very"}},
+				}},
+			}},
+		},
+		"NoHandler": {
+			reason: "A hook that specifies no handler should return an error.",
+			l:      &ContainerLifecycle{PreStop: &LifecycleHandler{}},
+			want:   want{err: errors.Errorf(errFmtNoLifecycleHandler, "cool-container", hookPreStop)},
+		},
+		"AmbiguousHandler": {
+			reason: "A hook that specifies more than one handler should return an error.",
+			l: &ContainerLifecycle{PostStart: &LifecycleHandler{
+				Exec:    &oamv1alpha2.ExecProbe{Command: []string{"true"}},
+				HTTPGet: &oamv1alpha2.HTTPGetProbe{Path: "/", Port: 80},
+			}},
+			want: want{err: errors.Errorf(errFmtAmbiguousLifecycleHandler, "cool-container", hookPostStart)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := lifecycle("cool-container", tc.l)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nlifecycle(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.l, got); diff != "" {
+				t.Errorf("\nReason: %s\nlifecycle(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestStartupProbe(t *testing.T) {
+	period := int32(10)
+	failures := int32(30)
+	tooMany := int32(361)
+
+	type want struct {
+		p   *corev1.Probe
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		p      *oamv1alpha2.ContainerHealthProbe
+		want   want
+	}{
+		"NoProbe": {
+			reason: "A nil startup probe should produce a nil Kubernetes probe.",
+		},
+		"HTTPGet": {
+			reason: "An HTTP startup probe should be translated, preserving explicit timings.",
+			p: &oamv1alpha2.ContainerHealthProbe{
+				HTTPGet:          &oamv1alpha2.HTTPGetProbe{Path: "/started", Port: 8080},
+				PeriodSeconds:    &period,
+				FailureThreshold: &failures,
+			},
+			want: want{p: &corev1.Probe{
+				Handler: corev1.Handler{
+					HTTPGet: &corev1.HTTPGetAction{Path: "/started", Port: intstr.FromInt(8080)},
+				},
+				PeriodSeconds:    period,
+				TimeoutSeconds:   defaultProbeTimeoutSeconds,
+				SuccessThreshold: defaultProbeSuccessThreshold,
+				FailureThreshold: failures,
+			}},
+		},
+		"Exec": {
+			reason: "An exec startup probe should be translated, with unset timings defaulted.",
+			p: &oamv1alpha2.ContainerHealthProbe{
+				Exec: &oamv1alpha2.ExecProbe{Command: []string{"cool", "--started"}},
+			},
+			want: want{p: &corev1.Probe{
+				Handler: corev1.Handler{
+					Exec: &corev1.ExecAction{Command: []string{"cool", "--started"}},
+				},
+				PeriodSeconds:    defaultProbePeriodSeconds,
+				TimeoutSeconds:   defaultProbeTimeoutSeconds,
+				SuccessThreshold: defaultProbeSuccessThreshold,
+				FailureThreshold: defaultProbeFailureThreshold,
+			}},
+		},
+		"TooLong": {
+			reason: "A startup probe that would allow more than an hour to start should return an error.",
+			p: &oamv1alpha2.ContainerHealthProbe{
+				Exec:             &oamv1alpha2.ExecProbe{Command: []string{"cool", "--started"}},
+				PeriodSeconds:    &period,
+				FailureThreshold: &tooMany,
+			},
+			want: want{err: errors.Errorf(errFmtStartupTooLong, "cool-container", 3610, maxStartupSeconds)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := startupProbe("cool-container", tc.p)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nstartupProbe(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.p, got); diff != "" {
+				t.Errorf("\nReason: %s\nstartupProbe(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+var _ workload.Translator = NewTranslator()
+
+func TestCustomLabelKey(t *testing.T) {
+	key := "example.org/workload"
+
+	w := containerizedWorkload(cwWithContainer(oamv1alpha2.Container{
+		Name:  "cool-container",
+		Image: "cool/image:latest",
+		Ports: []oamv1alpha2.ContainerPort{{Name: "http", Port: 8080}},
+	}))
+
+	objs, err := NewTranslator(WithLabelKey(key))(context.Background(), w)
+	if err != nil {
+		t.Fatalf("NewTranslator(...): %s", err)
+	}
+	objs, err = oamworkload.NewServiceInjector(oamworkload.WithLabelKey(key))(context.Background(), w, objs)
+	if err != nil {
+		t.Fatalf("NewServiceInjector(...): %s", err)
+	}
+	if len(objs) != 2 {
+		t.Fatalf("NewServiceInjector(...): want a Deployment and a Service, got %d objects", len(objs))
+	}
+
+	want := map[string]string{SelectorLabelKey: cwUID, key: cwUID}
+
+	d := objs[0].(*appsv1.Deployment)
+	if diff := cmp.Diff(map[string]string{SelectorLabelKey: cwUID}, d.Spec.Selector.MatchLabels); diff != "" {
+		t.Errorf("Deployment selector: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(want, d.Spec.Template.GetLabels()); diff != "" {
+		t.Errorf("Deployment pod labels: -want, +got:\n%s", diff)
+	}
+
+	s := objs[1].(*corev1.Service)
+	if diff := cmp.Diff(want, s.Spec.Selector); diff != "" {
+		t.Errorf("Service selector: -want, +got:\n%s", diff)
+	}
+}
+
+func TestTranslateContainerizedWorkload(t *testing.T) {
+	named := func(d *appsv1.Deployment) { d.SetName(cwName + DeploymentNameSuffix) }
+
+	type want struct {
+		result []resource.Object
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		w      resource.Workload
+		want   want
+	}{
+		"SingleContainer": {
+			reason: "A ContainerizedWorkload with one container should be translated into a Deployment named <workload>-deployment.",
+			w: containerizedWorkload(cwWithContainer(oamv1alpha2.Container{
+				Name:  "cool-container",
+				Image: "cool/image:latest",
+			})),
+			want: want{result: []resource.Object{deployment(named, dmWithContainer(corev1.Container{
+				Name:  "cool-container",
+				Image: "cool/image:latest",
+			}))}},
+		},
+		"MultipleContainers": {
+			reason: "A ContainerizedWorkload with multiple containers should be translated into a Deployment named <workload>-deployment with the same containers, in order.",
+			w: containerizedWorkload(
+				cwWithContainer(oamv1alpha2.Container{
+					Name:  "cool-container",
+					Image: "cool/image:latest",
+					Ports: []oamv1alpha2.ContainerPort{{Name: "http", Port: 8080}},
+				}),
+				cwWithContainer(oamv1alpha2.Container{
+					Name:  "cool-sidecar",
+					Image: "cool/sidecar:latest",
+					Ports: []oamv1alpha2.ContainerPort{{Name: "metrics", Port: 9090}},
+				}),
+			),
+			want: want{result: []resource.Object{deployment(
+				named,
+				dmWithContainer(corev1.Container{
+					Name:  "cool-container",
+					Image: "cool/image:latest",
+					Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+				}),
+				dmWithContainer(corev1.Container{
+					Name:  "cool-sidecar",
+					Image: "cool/sidecar:latest",
+					Ports: []corev1.ContainerPort{{Name: "metrics", ContainerPort: 9090}},
+				}),
+			)}},
+		},
+		"ErrorNotContainerizedWorkload": {
+			reason: "A workload that is not a ContainerizedWorkload should return an error.",
+			w:      &fake.Workload{},
+			want:   want{err: errors.New(errNotContainerizedWorkload)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := TranslateContainerizedWorkload(context.Background(), tc.w)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nTranslateContainerizedWorkload(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\nReason: %s\nTranslateContainerizedWorkload(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTranslateContainerizedWorkloadNames(t *testing.T) {
+	w := containerizedWorkload(cwWithContainer(oamv1alpha2.Container{
+		Name:  "cool-container",
+		Image: "cool/image:latest",
+		Ports: []oamv1alpha2.ContainerPort{{Name: "http", Port: 8080}},
+	}))
+
+	ctx := context.Background()
+	objs, err := TranslateContainerizedWorkload(ctx, w)
+	if err != nil {
+		t.Fatalf("TranslateContainerizedWorkload(...): %s", err)
+	}
+	objs, err = oamworkload.ServiceInjector(ctx, w, objs)
+	if err != nil {
+		t.Fatalf("ServiceInjector(...): %s", err)
+	}
+
+	got := map[string]string{}
+	for _, o := range objs {
+		got[o.GetObjectKind().GroupVersionKind().Kind] = o.GetName()
+	}
+	want := map[string]string{
+		"Deployment": cwName + DeploymentNameSuffix,
+		"Service":    cwName + oamworkload.ServiceNameSuffix,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ServiceInjector(TranslateContainerizedWorkload(...)): -want names, +got names:\n%s", diff)
+	}
+
+	objs, err = oamworkload.KubeAppWrapper(ctx, w, objs)
+	if err != nil {
+		t.Fatalf("KubeAppWrapper(...): %s", err)
+	}
+	app := objs[0].(*workloadv1alpha1.KubernetesApplication)
+	templates := []string{}
+	for _, kart := range app.Spec.ResourceTemplates {
+		templates = append(templates, kart.GetName())
+	}
+	wantTemplates := []string{cwName + DeploymentNameSuffix, cwName + oamworkload.ServiceNameSuffix + "-service"}
+	if diff := cmp.Diff(wantTemplates, templates); diff != "" {
+		t.Errorf("KubeAppWrapper(...): -want template names, +got template names:\n%s", diff)
+	}
+}
+
+func TestReplicasManagedByTrait(t *testing.T) {
+	w := containerizedWorkload(cwWithContainer(oamv1alpha2.Container{
+		Name:  "cool-container",
+		Image: "cool/image:latest",
+	}))
+	ms := &oamv1alpha2.ManualScalerTrait{Spec: oamv1alpha2.ManualScalerTraitSpec{ReplicaCount: 5}}
+
+	// The Deployment as it exists after a ManualScalerTrait has scaled it.
+	objs, err := Translator(context.Background(), w)
+	if err != nil {
+		t.Fatalf("Translator(...): %s", err)
+	}
+	existing, err := manualscaler.ApplyManualScaler(ms, objs)
+	if err != nil {
+		t.Fatalf("ApplyManualScaler(...): %s", err)
+	}
+
+	// Translating the workload again and merging the result into the existing
+	// Deployment, as an apply does, must not reset the trait's replicas.
+	objs, err = Translator(context.Background(), w)
+	if err != nil {
+		t.Fatalf("Translator(...): %s", err)
+	}
+	b, err := json.Marshal(objs[0])
+	if err != nil {
+		t.Fatalf("json.Marshal(...): %s", err)
+	}
+	d := existing[0].(*appsv1.Deployment)
+	if err := json.Unmarshal(b, d); err != nil {
+		t.Fatalf("json.Unmarshal(...): %s", err)
+	}
+
+	want := ms.Spec.ReplicaCount
+	if diff := cmp.Diff(&want, d.Spec.Replicas); diff != "" {
+		t.Errorf("Translator(...): replicas set by a ManualScalerTrait should survive translation: -want, +got:\n%s", diff)
+	}
+}
+
+func TestPriorityClasses(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		name   string
+		want   error
+	}{
+		"Known": {
+			reason: "A known priority class should be accepted.",
+			name:   "high-priority",
+		},
+		"Unknown": {
+			reason: "A priority class that is not known should return an error.",
+			name:   "highest-priority",
+			want:   errors.Errorf(errFmtUnknownPriorityClass, "highest-priority"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := containerizedWorkload(cwWithAnnotation(AnnotationKeyPriorityClassName, tc.name))
+			_, err := NewTranslator(WithPriorityClasses("low-priority", "high-priority"))(context.Background(), w)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nNewTranslator(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTranslatorWarnings(t *testing.T) {
+	value := "cool"
+	w := containerizedWorkload(cwWithContainer(oamv1alpha2.Container{
+		Name:  "cool-container",
+		Image: "cool/image:latest",
+		ConfigFiles: []oamv1alpha2.ContainerConfigFile{
+			{Path: "/etc/cool.conf", Value: &value},
+			{Path: "/etc/cool.secret", FromSecret: &oamv1alpha2.SecretKeySelector{Name: "cool", Key: "secret"}},
+		},
+		Resources: &oamv1alpha2.ContainerResources{
+			Volumes: []oamv1alpha2.VolumeResource{{
+				Name:      "cool-volume",
+				MouthPath: "/cool",
+				Disk:      &oamv1alpha2.DiskResource{Required: kresource.MustParse("1Gi")},
+			}},
+		},
+	}), cwWithAnnotation(AnnotationKeyVolumes, `[{"name":"cool-volume","emptyDir":{}}]`))
+
+	r := &oamworkload.WarningRecorder{}
+	if _, err := Translator(oamworkload.WithWarningRecorder(context.Background(), r), w); err != nil {
+		t.Fatalf("Translator(...): %s", err)
+	}
+
+	want := []string{
+		"spec.containers[cool-container].image: " + reasonLatestTag,
+		"spec.containers[cool-container].resources.volumes[cool-volume].disk: " + reasonVolumeDisk,
+	}
+	got := make([]string, 0)
+	for _, w := range r.Warnings() {
+		got = append(got, w.String())
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Translator(...): -want warnings, +got warnings:\n%s", diff)
+	}
+}
+
+func TestHostNetwork(t *testing.T) {
+	w := containerizedWorkload(
+		cwWithContainer(oamv1alpha2.Container{
+			Name:  "cool-container",
+			Image: "cool/image:1.0",
+			Ports: []oamv1alpha2.ContainerPort{{Name: "http", Port: 8080}},
+		}),
+		cwWithContainer(oamv1alpha2.Container{
+			Name:  "quiet-container",
+			Image: "quiet/image:1.0",
+		}),
+		cwWithAnnotation(AnnotationKeyHostNetwork, "true"),
+	)
+
+	r := &oamworkload.WarningRecorder{}
+	ctx := oamworkload.WithWarningRecorder(context.Background(), r)
+	objs, err := Translator(ctx, w)
+	if err != nil {
+		t.Fatalf("Translator(...): %s", err)
+	}
+
+	want := []oamworkload.Warning{{Field: "spec.containers[cool-container].ports", Reason: reasonHostNetworkPorts}}
+	if diff := cmp.Diff(want, r.Warnings()); diff != "" {
+		t.Errorf("Translator(...): -want warnings, +got warnings:\n%s", diff)
+	}
+
+	objs, err = oamworkload.ServiceInjector(ctx, w, objs)
+	if err != nil {
+		t.Fatalf("ServiceInjector(...): %s", err)
+	}
+	s := objs[1].(*corev1.Service)
+	if diff := cmp.Diff(intstr.FromInt(8080), s.Spec.Ports[0].TargetPort); diff != "" {
+		t.Errorf("ServiceInjector(...): Service of pods using the host network should target host ports: -want, +got:\n%s", diff)
+	}
+}
+
+func TestJob(t *testing.T) {
+	_, err := job(deployment(), corev1.RestartPolicyAlways)
+	if diff := cmp.Diff(errors.New(errJobRestartPolicyAlways), err, test.EquateErrors()); diff != "" {
+		t.Errorf("job(...): a Job target should reject the Always restart policy: -want error, +got error:\n%s", diff)
+	}
+}
+
+func TestValidateImageWarnings(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		image  string
+		want   []oamworkload.Warning
+	}{
+		"PinnedTag": {
+			reason: "An image with a specific tag should not be warned about.",
+			image:  "cool/image:v1.0.0",
+		},
+		"Digest": {
+			reason: "An image pinned to a digest should not be warned about.",
+			image:  "cool/image@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		},
+		"LatestTag": {
+			reason: "An image using the latest tag should be warned about.",
+			image:  "cool/image:latest",
+			want:   []oamworkload.Warning{{Field: "spec.containers[cool-container].image", Reason: reasonLatestTag}},
+		},
+		"Untagged": {
+			reason: "An image without a tag or digest implicitly uses the latest tag, and should be warned about.",
+			image:  "cool/image",
+			want:   []oamworkload.Warning{{Field: "spec.containers[cool-container].image", Reason: reasonLatestTag}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &oamworkload.WarningRecorder{}
+			err := validateImage(oamworkload.WithWarningRecorder(context.Background(), r), oamv1alpha2.Container{Name: "cool-container", Image: tc.image})
+			if err != nil {
+				t.Fatalf("\nReason: %s\nvalidateImage(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, r.Warnings()); diff != "" {
+				t.Errorf("\nReason: %s\nvalidateImage(...): -want warnings, +got warnings:\n%s", tc.reason, diff)
 			}
 		})
 	}
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerized
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+const (
+	errParseDaemonSet         = "unable to parse daemon set annotation"
+	errDaemonSetReplicas      = "daemon sets run one pod per node; the replicas annotation may not be set"
+	errDaemonSetRestartPolicy = "daemon sets must use the Always restart policy"
+)
+
+// AnnotationKeyDaemonSet may be set to "true" on a ContainerizedWorkload that
+// must run on every node, such as a log shipper or node agent. Such a
+// workload is translated into a DaemonSet rather than a Deployment. It may
+// not be combined with AnnotationKeyReplicas, or with a restart policy other
+// than Always.
+const AnnotationKeyDaemonSet = "core.oam.dev/daemon-set"
+
+var (
+	daemonSetKind       = reflect.TypeOf(appsv1.DaemonSet{}).Name()
+	daemonSetAPIVersion = appsv1.SchemeGroupVersion.String()
+)
+
+// isDaemonSet returns true if the supplied ContainerizedWorkload should be
+// translated into a DaemonSet.
+func isDaemonSet(cw *oamv1alpha2.ContainerizedWorkload) (bool, error) {
+	v, ok := cw.GetAnnotations()[AnnotationKeyDaemonSet]
+	if !ok {
+		return false, nil
+	}
+	ds, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, errors.Wrap(err, errParseDaemonSet)
+	}
+	if !ds {
+		return false, nil
+	}
+	if _, ok := cw.GetAnnotations()[AnnotationKeyReplicas]; ok {
+		return false, errors.New(errDaemonSetReplicas)
+	}
+	return true, nil
+}
+
+// daemonSet returns a DaemonSet that runs the pods of the supplied Deployment
+// on every node. The Deployment's selector and pod template are carried over,
+// but its replicas are not.
+func daemonSet(d *appsv1.Deployment) *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       daemonSetKind,
+			APIVersion: daemonSetAPIVersion,
+		},
+		ObjectMeta: *d.ObjectMeta.DeepCopy(),
+		Spec: appsv1.DaemonSetSpec{
+			Selector: d.Spec.Selector.DeepCopy(),
+			Template: *d.Spec.Template.DeepCopy(),
+		},
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerized
+
+import (
+	"encoding/json"
+	"net"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+const (
+	errParseDNSConfig         = "unable to parse DNS config annotation"
+	errFmtInvalidDNSPolicy    = "invalid DNS policy %q: must be one of ClusterFirst, ClusterFirstWithHostNet, Default, or None"
+	errDNSPolicyNoneNoServers = "a DNS config with at least one nameserver is required when the DNS policy is None"
+	errFmtTooManyNameservers  = "a DNS config may specify at most %d nameservers, got %d"
+	errFmtInvalidNameserver   = "invalid DNS nameserver %q: must be an IP address"
+)
+
+// maxDNSNameservers is the maximum number of nameservers a pod's DNS config
+// may specify.
+const maxDNSNameservers = 3
+
+// AnnotationKeyDNSPolicy may be set on a ContainerizedWorkload to specify the
+// DNS policy of its pods, i.e. one of ClusterFirst, ClusterFirstWithHostNet,
+// Default, or None. The Kubernetes default of ClusterFirst applies if the
+// annotation is omitted. The None policy ignores the cluster's DNS settings
+// entirely, and requires nameservers to be supplied using
+// AnnotationKeyDNSConfig.
+const AnnotationKeyDNSPolicy = "core.oam.dev/dns-policy"
+
+// AnnotationKeyDNSConfig may be set on a ContainerizedWorkload to add
+// nameservers, search domains, and resolver options to the DNS configuration
+// of its pods. Its value is a JSON encoded Kubernetes PodDNSConfig. It is
+// merged with the configuration derived from the DNS policy, unless the
+// policy is None.
+const AnnotationKeyDNSConfig = "core.oam.dev/dns-config"
+
+// dns returns the DNS policy and config of the supplied ContainerizedWorkload.
+func dns(cw *oamv1alpha2.ContainerizedWorkload) (corev1.DNSPolicy, *corev1.PodDNSConfig, error) {
+	a := cw.GetAnnotations()
+
+	var policy corev1.DNSPolicy
+	if v, ok := a[AnnotationKeyDNSPolicy]; ok {
+		switch policy = corev1.DNSPolicy(v); policy {
+		case corev1.DNSClusterFirst, corev1.DNSClusterFirstWithHostNet, corev1.DNSDefault, corev1.DNSNone:
+		default:
+			return "", nil, errors.Errorf(errFmtInvalidDNSPolicy, v)
+		}
+	}
+
+	var cfg *corev1.PodDNSConfig
+	if v, ok := a[AnnotationKeyDNSConfig]; ok {
+		cfg = &corev1.PodDNSConfig{}
+		if err := json.Unmarshal([]byte(v), cfg); err != nil {
+			return "", nil, errors.Wrap(err, errParseDNSConfig)
+		}
+	}
+
+	if policy == corev1.DNSNone && (cfg == nil || len(cfg.Nameservers) == 0) {
+		return "", nil, errors.New(errDNSPolicyNoneNoServers)
+	}
+	if cfg == nil {
+		return policy, nil, nil
+	}
+	if len(cfg.Nameservers) > maxDNSNameservers {
+		return "", nil, errors.Errorf(errFmtTooManyNameservers, maxDNSNameservers, len(cfg.Nameservers))
+	}
+	for _, ns := range cfg.Nameservers {
+		if net.ParseIP(ns) == nil {
+			return "", nil, errors.Errorf(errFmtInvalidNameserver, ns)
+		}
+	}
+	return policy, cfg, nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerized
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+	"github.com/crossplane/crossplane/pkg/oam/workload"
+)
+
+const (
+	errParseHostNetwork = "unable to parse host network annotation"
+	errParseHostPID     = "unable to parse host PID annotation"
+	errParseHostIPC     = "unable to parse host IPC annotation"
+)
+
+const reasonHostNetworkPorts = "the workload uses the host's network; container ports are bound to the same port of the node, and must not collide with ports used by the node or its other pods"
+
+// AnnotationKeyHostNetwork may be set to "true" on a ContainerizedWorkload
+// whose pods must use the network namespace of their node, such as a node
+// agent. Each container port is exposed as the host port of the same number.
+// An injected Service targets these host ports.
+const AnnotationKeyHostNetwork = "core.oam.dev/host-network"
+
+// AnnotationKeyHostPID may be set to "true" on a ContainerizedWorkload whose
+// pods must use the process ID namespace of their node.
+const AnnotationKeyHostPID = "core.oam.dev/host-pid"
+
+// AnnotationKeyHostIPC may be set to "true" on a ContainerizedWorkload whose
+// pods must use the IPC namespace of their node.
+const AnnotationKeyHostIPC = "core.oam.dev/host-ipc"
+
+// hostNamespaces configures the supplied pod spec to use the host namespaces
+// requested by the supplied ContainerizedWorkload. When the host's network is
+// used each container port is exposed as a host port, and a Warning is
+// recorded for each container that declares ports.
+func hostNamespaces(ctx context.Context, cw *oamv1alpha2.ContainerizedWorkload, ps *corev1.PodSpec) error {
+	a := cw.GetAnnotations()
+	flag := func(key, msg string) (bool, error) {
+		v, ok := a[key]
+		if !ok {
+			return false, nil
+		}
+		b, err := strconv.ParseBool(v)
+		return b, errors.Wrap(err, msg)
+	}
+
+	var err error
+	if ps.HostNetwork, err = flag(AnnotationKeyHostNetwork, errParseHostNetwork); err != nil {
+		return err
+	}
+	if ps.HostPID, err = flag(AnnotationKeyHostPID, errParseHostPID); err != nil {
+		return err
+	}
+	if ps.HostIPC, err = flag(AnnotationKeyHostIPC, errParseHostIPC); err != nil {
+		return err
+	}
+
+	if !ps.HostNetwork {
+		return nil
+	}
+	for i := range ps.Containers {
+		c := &ps.Containers[i]
+		for j := range c.Ports {
+			c.Ports[j].HostPort = c.Ports[j].ContainerPort
+		}
+		if len(c.Ports) > 0 {
+			workload.RecordWarning(ctx, workload.Warning{Field: fmt.Sprintf("spec.containers[%s].ports", c.Name), Reason: reasonHostNetworkPorts})
+		}
+	}
+	return nil
+}
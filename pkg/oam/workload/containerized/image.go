@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerized
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/distribution/reference"
+	"github.com/pkg/errors"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+	"github.com/crossplane/crossplane/pkg/oam/workload"
+)
+
+const (
+	errFmtEmptyImage   = "container %q: image must be specified"
+	errFmtInvalidImage = "container %q: invalid image %q"
+
+	reasonLatestTag = "the image uses the mutable latest tag; pin a specific tag or digest so that the image does not change unexpectedly"
+)
+
+const latestTag = "latest"
+
+// validateImage returns an error if the image of the supplied OAM Container is
+// empty or is not a valid image reference. A warning is recorded if the image
+// uses the latest tag, either explicitly or by omitting its tag and digest.
+func validateImage(ctx context.Context, c oamv1alpha2.Container) error {
+	if c.Image == "" {
+		return errors.Errorf(errFmtEmptyImage, c.Name)
+	}
+	named, err := reference.ParseNormalizedNamed(c.Image)
+	if err != nil {
+		return errors.Wrapf(err, errFmtInvalidImage, c.Name, c.Image)
+	}
+
+	if _, ok := named.(reference.Digested); ok {
+		return nil
+	}
+	if t, ok := named.(reference.Tagged); ok && t.Tag() != latestTag {
+		return nil
+	}
+	workload.RecordWarning(ctx, workload.Warning{Field: fmt.Sprintf("spec.containers[%s].image", c.Name), Reason: reasonLatestTag})
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerized
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+const (
+	errFmtInvalidRestartPolicy = "invalid restart policy %q: must be one of Always, OnFailure, or Never"
+	errJobRestartPolicyAlways  = "jobs may not use the Always restart policy"
+)
+
+// AnnotationKeyRestartPolicy may be set on a ContainerizedWorkload to specify
+// the restart policy of its pods. A ContainerizedWorkload with the OnFailure
+// or Never restart policy runs to completion, and is translated into a Job
+// rather than a Deployment. The Always restart policy is used if the
+// annotation is omitted.
+const AnnotationKeyRestartPolicy = "core.oam.dev/restart-policy"
+
+var (
+	jobKind       = reflect.TypeOf(batchv1.Job{}).Name()
+	jobAPIVersion = batchv1.SchemeGroupVersion.String()
+)
+
+// restartPolicy returns the restart policy of the supplied
+// ContainerizedWorkload.
+func restartPolicy(cw *oamv1alpha2.ContainerizedWorkload) (corev1.RestartPolicy, error) {
+	v, ok := cw.GetAnnotations()[AnnotationKeyRestartPolicy]
+	if !ok {
+		return corev1.RestartPolicyAlways, nil
+	}
+	switch p := corev1.RestartPolicy(v); p {
+	case corev1.RestartPolicyAlways, corev1.RestartPolicyOnFailure, corev1.RestartPolicyNever:
+		return p, nil
+	default:
+		return "", errors.Errorf(errFmtInvalidRestartPolicy, v)
+	}
+}
+
+// job returns a Job that runs the pods of the supplied Deployment to
+// completion using the supplied restart policy. Jobs select their own pods, so
+// the Deployment's selector and replicas are not carried over.
+func job(d *appsv1.Deployment, p corev1.RestartPolicy) (*batchv1.Job, error) {
+	if p == corev1.RestartPolicyAlways {
+		return nil, errors.New(errJobRestartPolicyAlways)
+	}
+
+	j := &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       jobKind,
+			APIVersion: jobAPIVersion,
+		},
+		ObjectMeta: *d.ObjectMeta.DeepCopy(),
+		Spec: batchv1.JobSpec{
+			Template: *d.Spec.Template.DeepCopy(),
+		},
+	}
+	j.Spec.Template.Spec.RestartPolicy = p
+	return j, nil
+}
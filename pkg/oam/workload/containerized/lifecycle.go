@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerized
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+const (
+	errFmtNoLifecycleHandler        = "container %q: %s hook must specify an exec or httpGet handler"
+	errFmtAmbiguousLifecycleHandler = "container %q: %s hook must specify only one of exec or httpGet"
+)
+
+// Lifecycle hook names, as used in error messages.
+const (
+	hookPostStart = "postStart"
+	hookPreStop   = "preStop"
+)
+
+// A ContainerLifecycle configures actions that are taken in response to
+// container lifecycle events.
+type ContainerLifecycle struct {
+	// PostStart is called immediately after the container is created.
+	PostStart *LifecycleHandler `json:"postStart,omitempty"`
+
+	// PreStop is called immediately before the container is terminated.
+	PreStop *LifecycleHandler `json:"preStop,omitempty"`
+}
+
+// A LifecycleHandler specifies the action taken by a lifecycle hook. Exactly
+// one of Exec or HTTPGet must be specified.
+type LifecycleHandler struct {
+	// Exec runs a command inside the container.
+	Exec *oamv1alpha2.ExecProbe `json:"exec,omitempty"`
+
+	// HTTPGet sends an HTTP GET request to the container.
+	HTTPGet *oamv1alpha2.HTTPGetProbe `json:"httpGet,omitempty"`
+}
+
+// lifecycle translates the supplied ContainerLifecycle of the named container
+// into a Kubernetes Lifecycle. A nil ContainerLifecycle produces a nil
+// Lifecycle.
+func lifecycle(container string, l *ContainerLifecycle) (*corev1.Lifecycle, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	postStart, err := lifecycleHandler(container, hookPostStart, l.PostStart)
+	if err != nil {
+		return nil, err
+	}
+	preStop, err := lifecycleHandler(container, hookPreStop, l.PreStop)
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.Lifecycle{PostStart: postStart, PreStop: preStop}, nil
+}
+
+// lifecycleHandler translates the LifecycleHandler of the supplied hook into a
+// Kubernetes Handler. Unlike probes, a hook must specify exactly one handler.
+func lifecycleHandler(container, hook string, h *LifecycleHandler) (*corev1.Handler, error) {
+	if h == nil {
+		return nil, nil
+	}
+
+	switch {
+	case h.Exec == nil && h.HTTPGet == nil:
+		return nil, errors.Errorf(errFmtNoLifecycleHandler, container, hook)
+	case h.Exec != nil && h.HTTPGet != nil:
+		return nil, errors.Errorf(errFmtAmbiguousLifecycleHandler, container, hook)
+	case h.Exec != nil:
+		return &corev1.Handler{Exec: &corev1.ExecAction{Command: copyStrings(h.Exec.Command)}}, nil
+	default:
+		return &corev1.Handler{HTTPGet: httpGetAction(h.HTTPGet)}, nil
+	}
+}
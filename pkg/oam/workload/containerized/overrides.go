@@ -0,0 +1,266 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerized
+
+import (
+	"encoding/json"
+	"net"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+const (
+	errParseContainerOverrides = "unable to parse container overrides annotation"
+	errFmtInvalidQuantity      = "container %q: invalid %s quantity %q for resource %q"
+	errParseInitContainers     = "unable to parse init containers annotation"
+	errParseReplicas           = "unable to parse replicas annotation"
+	errFmtNegativeReplicas     = "replicas annotation must not be negative, got %d"
+	errParseSecurityContext    = "unable to parse pod security context annotation"
+	errParseGracePeriod        = "unable to parse termination grace period annotation"
+	errFmtNegativeGracePeriod  = "termination grace period annotation must not be negative, got %d"
+	errParseHostAliases        = "unable to parse host aliases annotation"
+	errFmtInvalidHostAliasIP   = "invalid host alias IP address %q"
+	errFmtConflictingHostAlias = "host alias IP address %q is specified more than once with different hostnames"
+)
+
+// AnnotationKeyContainerOverrides may be set on a ContainerizedWorkload to
+// configure Kubernetes container settings that an OAM Container cannot
+// express. Its value is a JSON encoded map of container name to
+// ContainerOverrides.
+const AnnotationKeyContainerOverrides = "core.oam.dev/container-overrides"
+
+// AnnotationKeyInitContainers may be set on a ContainerizedWorkload to run
+// init containers before its containers start. Its value is a JSON encoded
+// array of OAM Containers, which run sequentially in the order supplied. Init
+// containers may be configured by AnnotationKeyContainerOverrides, like any
+// other container.
+const AnnotationKeyInitContainers = "core.oam.dev/init-containers"
+
+// AnnotationKeyImagePullSecrets may be set on a ContainerizedWorkload to
+// supply the names of secrets used to pull its container images, in addition
+// to those of its containers. Its value is a comma separated list of secret
+// names.
+const AnnotationKeyImagePullSecrets = "core.oam.dev/image-pull-secrets"
+
+// AnnotationKeyReplicas may be set on a ContainerizedWorkload to specify the
+// desired number of replicas of its Deployment. The replicas of the Deployment
+// are left unset if the annotation is omitted, so that they may be managed by a
+// ManualScalerTrait or HorizontalPodAutoscaler. Such a trait and the annotation
+// should not be used together; each would overwrite the replicas of the other.
+const AnnotationKeyReplicas = "core.oam.dev/replicas"
+
+// AnnotationKeyPodSecurityContext may be set on a ContainerizedWorkload to
+// configure the security context of its pods. Its value is a JSON encoded
+// Kubernetes PodSecurityContext. Per-container security contexts may be set
+// using AnnotationKeyContainerOverrides.
+const AnnotationKeyPodSecurityContext = "core.oam.dev/pod-security-context"
+
+// AnnotationKeyTerminationGracePeriod may be set on a ContainerizedWorkload to
+// specify how many seconds its pods are given to shut down gracefully. Zero
+// means pods are terminated immediately. The Kubernetes default of 30 seconds
+// applies if the annotation is omitted.
+const AnnotationKeyTerminationGracePeriod = "core.oam.dev/termination-grace-period-seconds"
+
+// AnnotationKeyHostAliases may be set on a ContainerizedWorkload to add
+// entries to the /etc/hosts file of its pods. Its value is a JSON encoded
+// array of Kubernetes HostAliases, each mapping an IP address to hostnames.
+const AnnotationKeyHostAliases = "core.oam.dev/host-aliases"
+
+// ContainerOverrides configure the Kubernetes container translated from an
+// OAM Container.
+type ContainerOverrides struct {
+	// Requests for compute resources, keyed by resource name. Requests are
+	// added to those derived from the OAM Container's resources, and take
+	// precedence over them.
+	Requests map[string]string `json:"requests,omitempty"`
+
+	// Limits on compute resources, keyed by resource name.
+	Limits map[string]string `json:"limits,omitempty"`
+
+	// Env variables that are appended to those derived from the OAM
+	// Container's environment. Unlike OAM environment variables these may be
+	// sourced from a ConfigMapKeyRef as well as a SecretKeyRef.
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// EnvFrom imports every key of a ConfigMap or Secret as an environment
+	// variable, optionally prefixed. Each source must reference exactly one
+	// named ConfigMap or Secret.
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// SecurityContext of the container. Fields that are omitted, such as
+	// runAsNonRoot, are left unset rather than defaulted to false.
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// Lifecycle hooks of the container, for example a preStop hook that
+	// delays shutdown until in-flight requests have drained.
+	Lifecycle *ContainerLifecycle `json:"lifecycle,omitempty"`
+
+	// StartupProbe of the container. Liveness and readiness probes are not
+	// run until it succeeds, protecting a slow starting container from being
+	// restarted before it is up. The container may take up to failureThreshold
+	// times periodSeconds to start, which may be no more than an hour.
+	StartupProbe *oamv1alpha2.ContainerHealthProbe `json:"startupProbe,omitempty"`
+
+	// Stdin allocates a buffer for the container's standard input, allowing
+	// it to be attached to, for example by kubectl attach. The container
+	// reads EOF from stdin if it is false.
+	Stdin bool `json:"stdin,omitempty"`
+
+	// StdinOnce closes the container's standard input after the first
+	// attached session disconnects, rather than keeping it open for
+	// subsequent sessions.
+	StdinOnce bool `json:"stdinOnce,omitempty"`
+
+	// TTY allocates a terminal for the container. It is usually set together
+	// with Stdin for interactive or debugging workloads.
+	TTY bool `json:"tty,omitempty"`
+}
+
+// containerOverrides returns the ContainerOverrides of the supplied
+// ContainerizedWorkload, keyed by container name.
+func containerOverrides(cw *oamv1alpha2.ContainerizedWorkload) (map[string]ContainerOverrides, error) {
+	v, ok := cw.GetAnnotations()[AnnotationKeyContainerOverrides]
+	if !ok {
+		return nil, nil
+	}
+	o := map[string]ContainerOverrides{}
+	if err := json.Unmarshal([]byte(v), &o); err != nil {
+		return nil, errors.Wrap(err, errParseContainerOverrides)
+	}
+	return o, nil
+}
+
+// initContainers returns the init containers of the supplied
+// ContainerizedWorkload, in order.
+func initContainers(cw *oamv1alpha2.ContainerizedWorkload) ([]oamv1alpha2.Container, error) {
+	v, ok := cw.GetAnnotations()[AnnotationKeyInitContainers]
+	if !ok {
+		return nil, nil
+	}
+	c := []oamv1alpha2.Container{}
+	if err := json.Unmarshal([]byte(v), &c); err != nil {
+		return nil, errors.Wrap(err, errParseInitContainers)
+	}
+	return c, nil
+}
+
+// replicas returns the desired replicas of the supplied ContainerizedWorkload,
+// or nil if it does not specify any.
+func replicas(cw *oamv1alpha2.ContainerizedWorkload) (*int32, error) {
+	v, ok := cw.GetAnnotations()[AnnotationKeyReplicas]
+	if !ok {
+		return nil, nil
+	}
+	r, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseReplicas)
+	}
+	if r < 0 {
+		return nil, errors.Errorf(errFmtNegativeReplicas, r)
+	}
+	i := int32(r)
+	return &i, nil
+}
+
+// podSecurityContext returns the pod security context of the supplied
+// ContainerizedWorkload, or nil if it specifies none.
+func podSecurityContext(cw *oamv1alpha2.ContainerizedWorkload) (*corev1.PodSecurityContext, error) {
+	v, ok := cw.GetAnnotations()[AnnotationKeyPodSecurityContext]
+	if !ok {
+		return nil, nil
+	}
+	sc := &corev1.PodSecurityContext{}
+	if err := json.Unmarshal([]byte(v), sc); err != nil {
+		return nil, errors.Wrap(err, errParseSecurityContext)
+	}
+	return sc, nil
+}
+
+// terminationGracePeriod returns the termination grace period of the supplied
+// ContainerizedWorkload, or nil if it specifies none.
+func terminationGracePeriod(cw *oamv1alpha2.ContainerizedWorkload) (*int64, error) {
+	v, ok := cw.GetAnnotations()[AnnotationKeyTerminationGracePeriod]
+	if !ok {
+		return nil, nil
+	}
+	p, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseGracePeriod)
+	}
+	if p < 0 {
+		return nil, errors.Errorf(errFmtNegativeGracePeriod, p)
+	}
+	return &p, nil
+}
+
+// hostAliases returns the host aliases of the supplied ContainerizedWorkload.
+// Aliases that repeat an earlier alias's IP address and hostnames are omitted.
+func hostAliases(cw *oamv1alpha2.ContainerizedWorkload) ([]corev1.HostAlias, error) {
+	v, ok := cw.GetAnnotations()[AnnotationKeyHostAliases]
+	if !ok {
+		return nil, nil
+	}
+	in := []corev1.HostAlias{}
+	if err := json.Unmarshal([]byte(v), &in); err != nil {
+		return nil, errors.Wrap(err, errParseHostAliases)
+	}
+
+	var out []corev1.HostAlias
+	seen := map[string][]string{}
+	for _, a := range in {
+		if net.ParseIP(a.IP) == nil {
+			return nil, errors.Errorf(errFmtInvalidHostAliasIP, a.IP)
+		}
+		hostnames := make([]string, len(a.Hostnames))
+		copy(hostnames, a.Hostnames)
+		sort.Strings(hostnames)
+		if existing, ok := seen[a.IP]; ok {
+			if !reflect.DeepEqual(existing, hostnames) {
+				return nil, errors.Errorf(errFmtConflictingHostAlias, a.IP)
+			}
+			continue
+		}
+		seen[a.IP] = hostnames
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+// resourceList parses the supplied map of resource name to quantity string
+// into a ResourceList. The supplied container and field names are used to
+// identify invalid quantities.
+func resourceList(container, field string, in map[string]string) (corev1.ResourceList, error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+	rl := corev1.ResourceList{}
+	for name, v := range in {
+		q, err := resource.ParseQuantity(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtInvalidQuantity, container, field, v, name)
+		}
+		rl[corev1.ResourceName(name)] = q
+	}
+	return rl, nil
+}
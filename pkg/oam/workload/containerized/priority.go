@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerized
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+const (
+	errFmtInvalidPriorityClass = "invalid priority class name %q: %s"
+	errFmtUnknownPriorityClass = "unknown priority class %q"
+)
+
+// AnnotationKeyPriorityClassName may be set on a ContainerizedWorkload to
+// specify the name of the PriorityClass of its pods. Pods of a higher
+// priority are scheduled first, and are the last to be evicted when a node is
+// under pressure. The cluster's default priority applies if the annotation is
+// omitted.
+const AnnotationKeyPriorityClassName = "core.oam.dev/priority-class-name"
+
+// priorityClassName returns the priority class name of the supplied
+// ContainerizedWorkload. If the supplied set of known priority classes is not
+// empty the name must be one of them.
+func priorityClassName(cw *oamv1alpha2.ContainerizedWorkload, known map[string]bool) (string, error) {
+	v, ok := cw.GetAnnotations()[AnnotationKeyPriorityClassName]
+	if !ok {
+		return "", nil
+	}
+	if errs := validation.IsDNS1123Subdomain(v); len(errs) > 0 {
+		return "", errors.Errorf(errFmtInvalidPriorityClass, v, strings.Join(errs, ", "))
+	}
+	if len(known) > 0 && !known[v] {
+		return "", errors.Errorf(errFmtUnknownPriorityClass, v)
+	}
+	return v, nil
+}
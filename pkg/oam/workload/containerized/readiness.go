@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerized
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+const (
+	errEmptyReadinessGate        = "readiness gate condition types must not be empty"
+	errFmtInvalidReadinessGate   = "invalid readiness gate condition type %q: %s"
+	errFmtDuplicateReadinessGate = "readiness gate condition type %q is specified more than once"
+)
+
+// AnnotationKeyReadinessGates may be set on a ContainerizedWorkload to add
+// readiness gates to its pods. Its value is a comma separated list of pod
+// condition types, for example target-health.elbv2.k8s.aws/cool-tg. A pod is
+// not ready until each of these conditions is true, in addition to its
+// containers being ready. The conditions are typically set by a load balancer
+// controller.
+const AnnotationKeyReadinessGates = "core.oam.dev/readiness-gates"
+
+// readinessGates returns the readiness gates of the supplied
+// ContainerizedWorkload.
+func readinessGates(cw *oamv1alpha2.ContainerizedWorkload) ([]corev1.PodReadinessGate, error) {
+	v, ok := cw.GetAnnotations()[AnnotationKeyReadinessGates]
+	if !ok {
+		return nil, nil
+	}
+
+	var gates []corev1.PodReadinessGate
+	seen := map[string]bool{}
+	for _, t := range strings.Split(v, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			return nil, errors.New(errEmptyReadinessGate)
+		}
+		if errs := validation.IsQualifiedName(t); len(errs) > 0 {
+			return nil, errors.Errorf(errFmtInvalidReadinessGate, t, strings.Join(errs, ", "))
+		}
+		if seen[t] {
+			return nil, errors.Errorf(errFmtDuplicateReadinessGate, t)
+		}
+		seen[t] = true
+		gates = append(gates, corev1.PodReadinessGate{ConditionType: corev1.PodConditionType(t)})
+	}
+	return gates, nil
+}
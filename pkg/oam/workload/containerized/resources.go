@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerized
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+const (
+	errFmtInvalidExtendedName     = "container %q: invalid extended resource name %q: %s"
+	errFmtInvalidExtendedQuantity = "container %q: invalid quantity %q for extended resource %q"
+	errFmtNotExtendedResource     = "container %q: invalid extended resource name %q: must be a domain-prefixed name outside kubernetes.io"
+)
+
+// ResourceNameGPU is the extended resource an OAM Container's GPU
+// requirement is translated into.
+const ResourceNameGPU corev1.ResourceName = "nvidia.com/gpu"
+
+// extendedResources returns the GPU and extended resources required by the
+// supplied OAM Container. Extended resource names must be qualified names
+// outside the kubernetes.io domain, e.g. example.com/widget.
+func extendedResources(c oamv1alpha2.Container) (corev1.ResourceList, error) {
+	rl := corev1.ResourceList{}
+	if c.Resources.GPU != nil {
+		rl[ResourceNameGPU] = c.Resources.GPU.Required
+	}
+	for _, e := range c.Resources.Extended {
+		name := corev1.ResourceName(e.Name)
+		if errs := validation.IsQualifiedName(e.Name); len(errs) > 0 {
+			return nil, errors.Errorf(errFmtInvalidExtendedName, c.Name, e.Name, strings.Join(errs, ", "))
+		}
+		if !isExtendedResource(name) {
+			return nil, errors.Errorf(errFmtNotExtendedResource, c.Name, e.Name)
+		}
+		q, err := resource.ParseQuantity(e.Required.String())
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtInvalidExtendedQuantity, c.Name, e.Required.String(), e.Name)
+		}
+		rl[name] = q
+	}
+	return rl, nil
+}
+
+// isExtendedResource returns true if the supplied resource name is that of an
+// extended resource, i.e. a domain-prefixed name outside the kubernetes.io
+// domain.
+func isExtendedResource(name corev1.ResourceName) bool {
+	n := string(name)
+	if !strings.Contains(n, "/") || strings.Contains(n, corev1.ResourceDefaultNamespacePrefix) {
+		return false
+	}
+	return !strings.HasPrefix(n, corev1.DefaultResourceRequestsPrefix)
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerized
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+const (
+	errParseUpdateStrategy       = "unable to parse update strategy annotation"
+	errFmtInvalidUpdateStrategy  = "invalid update strategy type %q: must be one of RollingUpdate or Recreate"
+	errRecreateWithRollingUpdate = "maxSurge and maxUnavailable may only be set for the RollingUpdate strategy"
+	errParseRevisionHistoryLimit = "unable to parse revision history limit annotation"
+	errFmtNegativeRevisionLimit  = "revision history limit annotation must not be negative, got %d"
+	errParseMinReadySeconds      = "unable to parse min ready seconds annotation"
+	errFmtNegativeMinReady       = "min ready seconds annotation must not be negative, got %d"
+	errParseProgressDeadline     = "unable to parse progress deadline annotation"
+	errFmtProgressDeadline       = "progress deadline of %d seconds must be greater than min ready seconds of %d"
+)
+
+// AnnotationKeyUpdateStrategy may be set on a ContainerizedWorkload to
+// configure how the pods of its Deployment are replaced when it is updated.
+// Its value is a JSON encoded Kubernetes DeploymentStrategy, for example
+// {"type":"RollingUpdate","rollingUpdate":{"maxSurge":1,"maxUnavailable":0}}.
+// The Kubernetes default rolling update applies if the annotation is omitted.
+const AnnotationKeyUpdateStrategy = "core.oam.dev/update-strategy"
+
+// AnnotationKeyRevisionHistoryLimit may be set on a ContainerizedWorkload to
+// specify how many old ReplicaSets of its Deployment are retained to allow
+// rollback. Zero means no old ReplicaSets are retained. The Kubernetes default
+// of 10 applies if the annotation is omitted.
+const AnnotationKeyRevisionHistoryLimit = "core.oam.dev/revision-history-limit"
+
+// AnnotationKeyMinReadySeconds may be set on a ContainerizedWorkload to
+// specify how many seconds a new pod of its Deployment must be ready, without
+// any of its containers crashing, before it is considered available. Pods are
+// considered ready according to their containers' readiness probes, so this
+// is typically paired with a readiness probe to keep a load balancer from
+// sending traffic to a pod prematurely. Pods are available as soon as they
+// are ready if the annotation is omitted.
+const AnnotationKeyMinReadySeconds = "core.oam.dev/min-ready-seconds"
+
+// AnnotationKeyProgressDeadline may be set on a ContainerizedWorkload to
+// specify how many seconds its Deployment may take to make progress before
+// its rollout is considered to have failed. It must be greater than the
+// value of AnnotationKeyMinReadySeconds. The Kubernetes default of 600
+// seconds applies if the annotation is omitted.
+const AnnotationKeyProgressDeadline = "core.oam.dev/progress-deadline-seconds"
+
+// updateStrategy returns the Deployment strategy of the supplied
+// ContainerizedWorkload. A strategy that omits its type is a rolling update.
+func updateStrategy(cw *oamv1alpha2.ContainerizedWorkload) (appsv1.DeploymentStrategy, error) {
+	v, ok := cw.GetAnnotations()[AnnotationKeyUpdateStrategy]
+	if !ok {
+		return appsv1.DeploymentStrategy{}, nil
+	}
+	s := appsv1.DeploymentStrategy{}
+	if err := json.Unmarshal([]byte(v), &s); err != nil {
+		return appsv1.DeploymentStrategy{}, errors.Wrap(err, errParseUpdateStrategy)
+	}
+	switch s.Type {
+	case appsv1.RollingUpdateDeploymentStrategyType:
+	case "":
+		s.Type = appsv1.RollingUpdateDeploymentStrategyType
+	case appsv1.RecreateDeploymentStrategyType:
+		if s.RollingUpdate != nil {
+			return appsv1.DeploymentStrategy{}, errors.New(errRecreateWithRollingUpdate)
+		}
+	default:
+		return appsv1.DeploymentStrategy{}, errors.Errorf(errFmtInvalidUpdateStrategy, s.Type)
+	}
+	return s, nil
+}
+
+// revisionHistoryLimit returns the revision history limit of the supplied
+// ContainerizedWorkload, or nil if it specifies none.
+func revisionHistoryLimit(cw *oamv1alpha2.ContainerizedWorkload) (*int32, error) {
+	v, ok := cw.GetAnnotations()[AnnotationKeyRevisionHistoryLimit]
+	if !ok {
+		return nil, nil
+	}
+	l, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseRevisionHistoryLimit)
+	}
+	if l < 0 {
+		return nil, errors.Errorf(errFmtNegativeRevisionLimit, l)
+	}
+	rl := int32(l)
+	return &rl, nil
+}
+
+// minReadySeconds returns the min ready seconds of the supplied
+// ContainerizedWorkload, or zero if it specifies none.
+func minReadySeconds(cw *oamv1alpha2.ContainerizedWorkload) (int32, error) {
+	v, ok := cw.GetAnnotations()[AnnotationKeyMinReadySeconds]
+	if !ok {
+		return 0, nil
+	}
+	s, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		return 0, errors.Wrap(err, errParseMinReadySeconds)
+	}
+	if s < 0 {
+		return 0, errors.Errorf(errFmtNegativeMinReady, s)
+	}
+	return int32(s), nil
+}
+
+// progressDeadline returns the progress deadline of the supplied
+// ContainerizedWorkload, or nil if it specifies none. The deadline must be
+// greater than the supplied min ready seconds.
+func progressDeadline(cw *oamv1alpha2.ContainerizedWorkload, minReady int32) (*int32, error) {
+	v, ok := cw.GetAnnotations()[AnnotationKeyProgressDeadline]
+	if !ok {
+		return nil, nil
+	}
+	s, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseProgressDeadline)
+	}
+	if s <= int64(minReady) {
+		return nil, errors.Errorf(errFmtProgressDeadline, s, minReady)
+	}
+	pd := int32(s)
+	return &pd, nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerized
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+// Errors returned while handling secret data must never include the data
+// itself, so underlying JSON errors are deliberately discarded.
+const (
+	errParseSecretData     = "unable to parse secret data annotation"
+	errFmtInvalidSecretKey = "invalid secret data key %q: %s"
+)
+
+// AnnotationKeySecretData may be set on a ContainerizedWorkload to supply
+// sensitive data that its containers consume. Its value is a JSON encoded
+// object of plaintext string values, which are stored in an Opaque Secret
+// named <workload-name>-secret. Containers may consume the data by
+// referencing that Secret from an environment variable or config file. Note
+// that the annotation may be read by anyone who can read the workload.
+const AnnotationKeySecretData = "core.oam.dev/secret-data"
+
+// SecretNameSuffix is appended to the name of a ContainerizedWorkload to derive
+// the name of the Secret that holds the data supplied by its
+// AnnotationKeySecretData annotation.
+const SecretNameSuffix = "-secret"
+
+// SecretVolumeNamePrefix prefixes the names of the volumes through which
+// config files sourced from secrets are mounted. Volumes declared using
+// AnnotationKeyVolumes may not use it.
+const SecretVolumeNamePrefix = "oam-secret-"
+
+var (
+	secretKind       = reflect.TypeOf(corev1.Secret{}).Name()
+	secretAPIVersion = corev1.SchemeGroupVersion.String()
+)
+
+// secretData returns a Secret holding the data supplied by the
+// AnnotationKeySecretData annotation of the supplied ContainerizedWorkload.
+// A nil Secret is returned if the annotation is not set.
+func secretData(labelKey string, cw *oamv1alpha2.ContainerizedWorkload) (*corev1.Secret, error) {
+	v, ok := cw.GetAnnotations()[AnnotationKeySecretData]
+	if !ok {
+		return nil, nil
+	}
+	in := map[string]string{}
+	if err := json.Unmarshal([]byte(v), &in); err != nil {
+		return nil, errors.New(errParseSecretData)
+	}
+
+	data := make(map[string][]byte, len(in))
+	for k, v := range in {
+		if errs := validation.IsConfigMapKey(k); len(errs) > 0 {
+			return nil, errors.Errorf(errFmtInvalidSecretKey, k, strings.Join(errs, ", "))
+		}
+		data[k] = []byte(v)
+	}
+
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       secretKind,
+			APIVersion: secretAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: cw.GetName() + SecretNameSuffix,
+			Labels: map[string]string{
+				labelKey: string(cw.GetUID()),
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}, nil
+}
+
+// secretFiles returns a volume for each secret referenced by the config files
+// of the supplied containers, and the volume mounts that write each file to
+// its path keyed by container name. Config files with an inline value are
+// skipped; see configFiles.
+func secretFiles(containers []oamv1alpha2.Container) ([]corev1.Volume, map[string][]corev1.VolumeMount) {
+	var vols []corev1.Volume
+	names := map[string]string{}
+	mounts := map[string][]corev1.VolumeMount{}
+	for _, c := range containers {
+		for _, f := range c.ConfigFiles {
+			if f.Value != nil || f.FromSecret == nil {
+				continue
+			}
+			name, ok := names[f.FromSecret.Name]
+			if !ok {
+				name = SecretVolumeNamePrefix + strconv.Itoa(len(vols))
+				names[f.FromSecret.Name] = name
+				vols = append(vols, corev1.Volume{
+					Name: name,
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{SecretName: f.FromSecret.Name},
+					},
+				})
+			}
+			mounts[c.Name] = append(mounts[c.Name], corev1.VolumeMount{
+				Name:      name,
+				MountPath: f.Path,
+				SubPath:   f.FromSecret.Key,
+				ReadOnly:  true,
+			})
+		}
+	}
+	return vols, mounts
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerized
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+const (
+	errFmtInvalidServiceAccount = "invalid service account name %q: %s"
+	errCreateServiceAccount     = "a service account name must be specified in order to create a service account"
+)
+
+// AnnotationKeyServiceAccount may be set on a ContainerizedWorkload to specify
+// the name of the service account its pods run as. Pods run as the namespace's
+// default service account if the annotation is omitted.
+const AnnotationKeyServiceAccount = "core.oam.dev/service-account"
+
+// AnnotationKeyCreateServiceAccount may be set to "true" on a
+// ContainerizedWorkload to create the service account named by
+// AnnotationKeyServiceAccount, rather than use an existing one.
+const AnnotationKeyCreateServiceAccount = "core.oam.dev/create-service-account"
+
+var (
+	serviceAccountKind       = reflect.TypeOf(corev1.ServiceAccount{}).Name()
+	serviceAccountAPIVersion = corev1.SchemeGroupVersion.String()
+)
+
+// serviceAccount returns the name of the service account of the supplied
+// ContainerizedWorkload, and the ServiceAccount to create if the workload
+// requests one be created. A nil ServiceAccount is returned otherwise.
+func serviceAccount(labelKey string, cw *oamv1alpha2.ContainerizedWorkload) (string, *corev1.ServiceAccount, error) {
+	a := cw.GetAnnotations()
+	name := a[AnnotationKeyServiceAccount]
+	create := a[AnnotationKeyCreateServiceAccount] == "true"
+
+	if name == "" {
+		if create {
+			return "", nil, errors.New(errCreateServiceAccount)
+		}
+		return "", nil, nil
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return "", nil, errors.Errorf(errFmtInvalidServiceAccount, name, strings.Join(errs, ", "))
+	}
+	if !create {
+		return name, nil, nil
+	}
+
+	return name, &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       serviceAccountKind,
+			APIVersion: serviceAccountAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				labelKey: string(cw.GetUID()),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerized
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+const (
+	errParseVolumes             = "unable to parse volumes annotation"
+	errFmtUnsupportedVolume     = "volume %q must specify exactly one of emptyDir, configMap, secret, or persistentVolumeClaim"
+	errFmtUndeclaredVolumeMount = "container %q: volume mount %q does not reference a declared volume"
+	errFmtReservedVolume        = "volume %q uses a name reserved for config files"
+)
+
+// AnnotationKeyVolumes may be set on a ContainerizedWorkload to declare the
+// volumes that its containers mount. Its value is a JSON encoded array of
+// Kubernetes volumes. When it is set every volume mount must reference one of
+// the declared volumes. Only emptyDir, configMap, secret, and
+// persistentVolumeClaim volume sources are supported. No volume may be named
+// ConfigVolumeName, or have a name prefixed with SecretVolumeNamePrefix.
+const AnnotationKeyVolumes = "core.oam.dev/volumes"
+
+// volumes returns the volumes declared by the supplied ContainerizedWorkload.
+func volumes(cw *oamv1alpha2.ContainerizedWorkload) ([]corev1.Volume, error) {
+	v, ok := cw.GetAnnotations()[AnnotationKeyVolumes]
+	if !ok {
+		return nil, nil
+	}
+	vols := []corev1.Volume{}
+	if err := json.Unmarshal([]byte(v), &vols); err != nil {
+		return nil, errors.Wrap(err, errParseVolumes)
+	}
+	for _, vol := range vols {
+		if vol.Name == ConfigVolumeName || strings.HasPrefix(vol.Name, SecretVolumeNamePrefix) {
+			return nil, errors.Errorf(errFmtReservedVolume, vol.Name)
+		}
+		if !supportedVolumeSource(vol.VolumeSource) {
+			return nil, errors.Errorf(errFmtUnsupportedVolume, vol.Name)
+		}
+	}
+	return vols, nil
+}
+
+// supportedVolumeSource returns true if the supplied VolumeSource specifies
+// exactly one supported source, and no unsupported sources.
+func supportedVolumeSource(s corev1.VolumeSource) bool {
+	n := 0
+	if s.EmptyDir != nil {
+		n++
+	}
+	if s.ConfigMap != nil {
+		n++
+	}
+	if s.Secret != nil {
+		n++
+	}
+	if s.PersistentVolumeClaim != nil {
+		n++
+	}
+	return n == 1 && s == (corev1.VolumeSource{
+		EmptyDir:              s.EmptyDir,
+		ConfigMap:             s.ConfigMap,
+		Secret:                s.Secret,
+		PersistentVolumeClaim: s.PersistentVolumeClaim,
+	})
+}
+
+// validateVolumeMounts returns an error if any of the supplied containers
+// mount a volume that is not one of the supplied volumes.
+func validateVolumeMounts(vols []corev1.Volume, containers ...corev1.Container) error {
+	declared := make(map[string]bool, len(vols))
+	for _, v := range vols {
+		declared[v.Name] = true
+	}
+	for _, c := range containers {
+		for _, m := range c.VolumeMounts {
+			if !declared[m.Name] {
+				return errors.Errorf(errFmtUndeclaredVolumeMount, c.Name, m.Name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// An objectKey uniquely identifies an object within a workload translation.
+type objectKey struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+}
+
+func keyOf(o resource.Object) objectKey {
+	return objectKey{
+		gvk:       o.GetObjectKind().GroupVersionKind(),
+		namespace: o.GetNamespace(),
+		name:      o.GetName(),
+	}
+}
+
+// Dedupe returns the supplied objects with duplicates removed. Objects are
+// duplicates if they share a group, version, kind, namespace, and name. The
+// last of a set of duplicates wins, but takes the position of the first so
+// that the order in which distinct objects were first produced is preserved.
+func Dedupe(objs []resource.Object) []resource.Object {
+	if objs == nil {
+		return nil
+	}
+
+	deduped := make([]resource.Object, 0, len(objs))
+	idx := make(map[objectKey]int, len(objs))
+	for _, o := range objs {
+		k := keyOf(o)
+		if i, ok := idx[k]; ok {
+			deduped[i] = o
+			continue
+		}
+		idx[k] = len(deduped)
+		deduped = append(deduped, o)
+	}
+	return deduped
+}
+
+// DedupeWrapper removes duplicate objects from a workload translation, as
+// Dedupe does. It should be run after any wrappers that may emit an object an
+// earlier stage already produced, and before KubeAppWrapper, which would
+// otherwise produce a resource template for each duplicate.
+func DedupeWrapper(ctx context.Context, w resource.Workload, objs []resource.Object) ([]resource.Object, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return Dedupe(objs), nil
+}
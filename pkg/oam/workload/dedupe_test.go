@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ workload.TranslationWrapper = DedupeWrapper
+
+func TestDedupe(t *testing.T) {
+	svcOtherNamespace := service()
+	svcOtherNamespace.SetNamespace("other")
+
+	cases := map[string]struct {
+		reason string
+		objs   []resource.Object
+		want   []resource.Object
+	}{
+		"Nil": {
+			reason: "Deduping nil objects should return nil",
+			objs:   nil,
+			want:   nil,
+		},
+		"NoDuplicates": {
+			reason: "Objects that differ only in kind or namespace are distinct, and should be returned unchanged",
+			objs:   []resource.Object{configMap(), secret(), service(), svcOtherNamespace},
+			want:   []resource.Object{configMap(), secret(), service(), svcOtherNamespace},
+		},
+		"DuplicateServices": {
+			reason: "Only the last written of a set of duplicate Services should be returned, in the position of the first",
+			objs: []resource.Object{
+				service(),
+				configMap(),
+				service(sWithType(corev1.ServiceTypeClusterIP)),
+				secret(),
+				service(sWithType(corev1.ServiceTypeNodePort)),
+			},
+			want: []resource.Object{
+				service(sWithType(corev1.ServiceTypeNodePort)),
+				configMap(),
+				secret(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Dedupe(tc.objs)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nDedupe(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDedupeWrapper(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+		},
+	}
+
+	objs := []resource.Object{service(), service(sWithType(corev1.ServiceTypeClusterIP))}
+	want := []resource.Object{service(sWithType(corev1.ServiceTypeClusterIP))}
+
+	got, err := DedupeWrapper(context.Background(), w, objs)
+	if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+		t.Errorf("DedupeWrapper(...): -want error, +got error:\n%s", diff)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("DedupeWrapper(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+const (
+	reasonFmtUnavailable      = "%d of %d desired replicas are available"
+	reasonStatusNotObserved   = "status has not been observed for the latest generation"
+	reasonDeploymentAvailable = "all desired replicas are available"
+)
+
+// A HealthStatus indicates whether a workload is healthy.
+type HealthStatus string
+
+// Health statuses.
+const (
+	HealthStatusHealthy   HealthStatus = "Healthy"
+	HealthStatusUnhealthy HealthStatus = "Unhealthy"
+	HealthStatusUnknown   HealthStatus = "Unknown"
+)
+
+// A WorkloadHealth describes the health of a translated Deployment.
+type WorkloadHealth struct {
+	// Name of the Deployment.
+	Name string
+
+	// Namespace of the Deployment.
+	Namespace string
+
+	// Status of the Deployment.
+	Status HealthStatus
+
+	// Reason for the status, suitable for display to humans.
+	Reason string
+
+	// DesiredReplicas of the Deployment.
+	DesiredReplicas int32
+
+	// AvailableReplicas of the Deployment.
+	AvailableReplicas int32
+}
+
+// A ScopeHealth describes the aggregate health of the workloads in a scope.
+type ScopeHealth struct {
+	// Status of the scope. A scope is unhealthy if any of its workloads are
+	// unhealthy, unknown if none are unhealthy but the health of any is
+	// unknown, and otherwise healthy.
+	Status HealthStatus
+
+	// Workloads in the scope, in the order they were supplied.
+	Workloads []WorkloadHealth
+}
+
+// Unhealthy returns the workloads in the scope that are not healthy,
+// including those whose health is unknown.
+func (h ScopeHealth) Unhealthy() []WorkloadHealth {
+	var u []WorkloadHealth
+	for _, w := range h.Workloads {
+		if w.Status != HealthStatusHealthy {
+			u = append(u, w)
+		}
+	}
+	return u
+}
+
+// AggregateHealth computes the aggregate health of the supplied Deployments,
+// which should include their live status. A Deployment is healthy if at least
+// as many replicas are available as are desired. Its health is unknown if
+// its controller has not yet observed its latest generation. A scope with no
+// Deployments is healthy.
+func AggregateHealth(ds []*appsv1.Deployment) ScopeHealth {
+	h := ScopeHealth{Status: HealthStatusHealthy, Workloads: make([]WorkloadHealth, 0, len(ds))}
+	for _, d := range ds {
+		wh := deploymentHealth(d)
+		h.Workloads = append(h.Workloads, wh)
+
+		switch {
+		case wh.Status == HealthStatusUnhealthy:
+			h.Status = HealthStatusUnhealthy
+		case wh.Status == HealthStatusUnknown && h.Status == HealthStatusHealthy:
+			h.Status = HealthStatusUnknown
+		}
+	}
+	return h
+}
+
+// deploymentHealth returns the health of the supplied Deployment. A
+// Deployment that does not specify its replicas desires one replica.
+func deploymentHealth(d *appsv1.Deployment) WorkloadHealth {
+	h := WorkloadHealth{
+		Name:              d.GetName(),
+		Namespace:         d.GetNamespace(),
+		DesiredReplicas:   1,
+		AvailableReplicas: d.Status.AvailableReplicas,
+	}
+	if d.Spec.Replicas != nil {
+		h.DesiredReplicas = *d.Spec.Replicas
+	}
+
+	switch {
+	case d.Status.ObservedGeneration < d.GetGeneration():
+		h.Status = HealthStatusUnknown
+		h.Reason = reasonStatusNotObserved
+	case h.AvailableReplicas < h.DesiredReplicas:
+		h.Status = HealthStatusUnhealthy
+		h.Reason = fmt.Sprintf(reasonFmtUnavailable, h.AvailableReplicas, h.DesiredReplicas)
+	default:
+		h.Status = HealthStatusHealthy
+		h.Reason = reasonDeploymentAvailable
+	}
+	return h
+}
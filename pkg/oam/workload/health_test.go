@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+func TestAggregateHealth(t *testing.T) {
+	replicas := int32(3)
+
+	withStatus := func(name string, desired *int32, available int32, generation, observed int64) *appsv1.Deployment {
+		d := deployment()
+		d.SetName(name)
+		d.SetGeneration(generation)
+		d.Spec.Replicas = desired
+		d.Status.AvailableReplicas = available
+		d.Status.ObservedGeneration = observed
+		return d
+	}
+
+	healthy := WorkloadHealth{
+		Name:              "healthy",
+		Status:            HealthStatusHealthy,
+		Reason:            reasonDeploymentAvailable,
+		DesiredReplicas:   3,
+		AvailableReplicas: 3,
+	}
+	unhealthy := WorkloadHealth{
+		Name:              "unhealthy",
+		Status:            HealthStatusUnhealthy,
+		Reason:            fmt.Sprintf(reasonFmtUnavailable, 0, 1),
+		DesiredReplicas:   1,
+		AvailableReplicas: 0,
+	}
+	unknown := WorkloadHealth{
+		Name:              "unknown",
+		Status:            HealthStatusUnknown,
+		Reason:            reasonStatusNotObserved,
+		DesiredReplicas:   3,
+		AvailableReplicas: 3,
+	}
+
+	type want struct {
+		health    ScopeHealth
+		unhealthy []WorkloadHealth
+	}
+
+	cases := map[string]struct {
+		reason string
+		ds     []*appsv1.Deployment
+		want   want
+	}{
+		"NoDeployments": {
+			reason: "A scope with no Deployments should be healthy.",
+			want: want{health: ScopeHealth{
+				Status:    HealthStatusHealthy,
+				Workloads: []WorkloadHealth{},
+			}},
+		},
+		"AllHealthy": {
+			reason: "A scope whose Deployments all have their desired replicas available should be healthy.",
+			ds: []*appsv1.Deployment{
+				withStatus("healthy", &replicas, 3, 1, 1),
+				withStatus("healthy", &replicas, 3, 2, 2),
+			},
+			want: want{health: ScopeHealth{
+				Status:    HealthStatusHealthy,
+				Workloads: []WorkloadHealth{healthy, healthy},
+			}},
+		},
+		"OneUnhealthy": {
+			reason: "A scope with an unavailable Deployment should be unhealthy, and list it. A Deployment that omits its replicas desires one.",
+			ds: []*appsv1.Deployment{
+				withStatus("healthy", &replicas, 3, 1, 1),
+				withStatus("unhealthy", nil, 0, 1, 1),
+				withStatus("unknown", &replicas, 3, 2, 1),
+			},
+			want: want{
+				health: ScopeHealth{
+					Status:    HealthStatusUnhealthy,
+					Workloads: []WorkloadHealth{healthy, unhealthy, unknown},
+				},
+				unhealthy: []WorkloadHealth{unhealthy, unknown},
+			},
+		},
+		"UnknownStatus": {
+			reason: "A scope with a Deployment whose latest generation has not been observed should be unknown.",
+			ds: []*appsv1.Deployment{
+				withStatus("healthy", &replicas, 3, 1, 1),
+				withStatus("unknown", &replicas, 3, 2, 1),
+			},
+			want: want{
+				health: ScopeHealth{
+					Status:    HealthStatusUnknown,
+					Workloads: []WorkloadHealth{healthy, unknown},
+				},
+				unhealthy: []WorkloadHealth{unknown},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := AggregateHealth(tc.ds)
+			if diff := cmp.Diff(tc.want.health, got); diff != "" {
+				t.Errorf("\nReason: %s\nAggregateHealth(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.unhealthy, got.Unhealthy()); diff != "" {
+				t.Errorf("\nReason: %s\nUnhealthy(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
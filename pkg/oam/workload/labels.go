@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Recommended labels applied by RecommendedLabeler. See
+// https://kubernetes.io/docs/concepts/overview/working-with-objects/common-labels/
+const (
+	LabelKeyName      = "app.kubernetes.io/name"
+	LabelKeyInstance  = "app.kubernetes.io/instance"
+	LabelKeyManagedBy = "app.kubernetes.io/managed-by"
+)
+
+// ManagedBy is the value of the LabelKeyManagedBy label applied by
+// RecommendedLabeler.
+const ManagedBy = "crossplane-oam"
+
+// RecommendedLabeler labels each translated object, and the pod template of
+// each object that manages pods, with the Kubernetes recommended labels. The
+// name label is the workload's name, and the instance label its UID. Names
+// longer than the 63 character label value limit are truncated, and suffixed
+// with a hash of the original name to keep them unique. Labels that are
+// already set, including LabelKey, are left unchanged. RecommendedLabeler
+// should be run before KubeAppWrapper in order for the wrapped objects to be
+// labelled.
+func RecommendedLabeler(ctx context.Context, w resource.Workload, objs []resource.Object) ([]resource.Object, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	labels := map[string]string{
+		LabelKeyName:      truncate(w.GetName(), validation.LabelValueMaxLength),
+		LabelKeyInstance:  string(w.GetUID()),
+		LabelKeyManagedBy: ManagedBy,
+	}
+
+	for _, o := range objs {
+		o.SetLabels(withDefaultLabels(o.GetLabels(), labels))
+		if t := PodTemplate(o); t != nil {
+			t.SetLabels(withDefaultLabels(t.GetLabels(), labels))
+		}
+	}
+	return objs, nil
+}
+
+// withDefaultLabels returns the supplied labels, adding any of the supplied
+// defaults that are not already set.
+func withDefaultLabels(labels, defaults map[string]string) map[string]string {
+	if labels == nil {
+		labels = make(map[string]string, len(defaults))
+	}
+	for k, v := range defaults {
+		if _, ok := labels[k]; !ok {
+			labels[k] = v
+		}
+	}
+	return labels
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+)
+
+var _ workload.TranslationWrapper = RecommendedLabeler
+
+func TestRecommendedLabeler(t *testing.T) {
+	longName := strings.Repeat("a", 70)
+
+	withLabels := func(l map[string]string) deploymentModifier {
+		return func(d *appsv1.Deployment) {
+			d.SetLabels(l)
+		}
+	}
+
+	type want struct {
+		labels    map[string]string
+		podLabels map[string]string
+	}
+
+	cases := map[string]struct {
+		reason string
+		name   string
+		d      *appsv1.Deployment
+		want   want
+	}{
+		"DeploymentAndPodTemplate": {
+			reason: "The recommended labels should be added to the Deployment and its pod template, without clobbering LabelKey.",
+			name:   workloadName,
+			d:      deployment(),
+			want: want{
+				labels: map[string]string{
+					LabelKeyName:      workloadName,
+					LabelKeyInstance:  workloadUID,
+					LabelKeyManagedBy: ManagedBy,
+				},
+				podLabels: map[string]string{
+					LabelKey:          workloadUID,
+					LabelKeyName:      workloadName,
+					LabelKeyInstance:  workloadUID,
+					LabelKeyManagedBy: ManagedBy,
+				},
+			},
+		},
+		"ExistingLabels": {
+			reason: "Recommended labels that are already set should not be overwritten.",
+			name:   workloadName,
+			d:      deployment(withLabels(map[string]string{LabelKeyName: "cool-app"})),
+			want: want{
+				labels: map[string]string{
+					LabelKeyName:      "cool-app",
+					LabelKeyInstance:  workloadUID,
+					LabelKeyManagedBy: ManagedBy,
+				},
+				podLabels: map[string]string{
+					LabelKey:          workloadUID,
+					LabelKeyName:      workloadName,
+					LabelKeyInstance:  workloadUID,
+					LabelKeyManagedBy: ManagedBy,
+				},
+			},
+		},
+		"LongName": {
+			reason: "Workload names longer than the label value limit should be truncated.",
+			name:   longName,
+			d:      deployment(),
+			want: want{
+				labels: map[string]string{
+					LabelKeyName:      truncate(longName, 63),
+					LabelKeyInstance:  workloadUID,
+					LabelKeyManagedBy: ManagedBy,
+				},
+				podLabels: map[string]string{
+					LabelKey:          workloadUID,
+					LabelKeyName:      truncate(longName, 63),
+					LabelKeyInstance:  workloadUID,
+					LabelKeyManagedBy: ManagedBy,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := &fake.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      tc.name,
+					Namespace: workloadNamespace,
+					UID:       types.UID(workloadUID),
+				},
+			}
+
+			objs, err := RecommendedLabeler(context.Background(), w, []resource.Object{tc.d})
+			if err != nil {
+				t.Fatalf("RecommendedLabeler(...): %s", err)
+			}
+			d := objs[0].(*appsv1.Deployment)
+
+			if diff := cmp.Diff(tc.want.labels, d.GetLabels()); diff != "" {
+				t.Errorf("\nReason: %s\nRecommendedLabeler(...): -want Deployment labels, +got Deployment labels:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.podLabels, d.Spec.Template.GetLabels()); diff != "" {
+				t.Errorf("\nReason: %s\nRecommendedLabeler(...): -want pod template labels, +got pod template labels:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
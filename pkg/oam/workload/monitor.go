@@ -0,0 +1,146 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errFmtNoMetricsPort = "no Service selecting the workload exposes metrics port %q"
+)
+
+// Annotations that may be set on a workload to configure the ServiceMonitor
+// produced by ServiceMonitorInjector.
+const (
+	// AnnotationKeyMetricsPort is the name of the Service port from which
+	// metrics should be scraped. No ServiceMonitor is produced if it is not
+	// set.
+	AnnotationKeyMetricsPort = "core.oam.dev/metrics-port"
+
+	// AnnotationKeyMetricsPath is the HTTP path from which metrics should be
+	// scraped. Metrics are scraped from /metrics if it is not set.
+	AnnotationKeyMetricsPath = "core.oam.dev/metrics-path"
+)
+
+// ServiceMonitorNameSuffix is appended to the name of a workload in order to
+// derive the name of its ServiceMonitor.
+const ServiceMonitorNameSuffix = "-monitor"
+
+const defaultMetricsPath = "/metrics"
+
+// ServiceMonitorGroupVersionKind is the GroupVersionKind of the Prometheus
+// Operator ServiceMonitor produced by ServiceMonitorInjector.
+var ServiceMonitorGroupVersionKind = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "ServiceMonitor",
+}
+
+// ServiceMonitorInjector adds a Prometheus Operator ServiceMonitor that
+// scrapes the metrics port of the Service that selects the workload's pods.
+// It should be run after ServiceInjector. A ServiceMonitor is only added if
+// the workload names its metrics port using the AnnotationKeyMetricsPort
+// annotation, so that clusters without the ServiceMonitor CRD are unaffected.
+// The ServiceMonitor is named <workload-name>-monitor, and selects Services
+// using LabelKey. No ServiceMonitor is added if the translation already
+// includes one with that name, or has already been wrapped by KubeAppWrapper.
+func ServiceMonitorInjector(ctx context.Context, w resource.Workload, objs []resource.Object) ([]resource.Object, error) {
+	return NewServiceMonitorInjector()(ctx, w, objs)
+}
+
+// NewServiceMonitorInjector returns a TranslationWrapper that behaves like
+// ServiceMonitorInjector, configured by the supplied options.
+func NewServiceMonitorInjector(options ...WrapperOption) workload.TranslationWrapper {
+	opts := newWrapperOptions(options...)
+	return func(ctx context.Context, w resource.Workload, objs []resource.Object) ([]resource.Object, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		port, ok := w.GetAnnotations()[AnnotationKeyMetricsPort]
+		if !ok || wrapped(objs) || hasServiceMonitor(w, objs) {
+			return objs, nil
+		}
+		if !exposesPort(opts.labelKey, w, port, objs) {
+			return nil, errors.Errorf(errFmtNoMetricsPort, port)
+		}
+
+		path := defaultMetricsPath
+		if p, ok := w.GetAnnotations()[AnnotationKeyMetricsPath]; ok {
+			path = p
+		}
+
+		sm := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{
+						opts.labelKey: string(w.GetUID()),
+					},
+				},
+				"endpoints": []interface{}{
+					map[string]interface{}{
+						"port": port,
+						"path": path,
+					},
+				},
+			},
+		}}
+		sm.SetGroupVersionKind(ServiceMonitorGroupVersionKind)
+		sm.SetName(w.GetName() + ServiceMonitorNameSuffix)
+		sm.SetLabels(map[string]string{opts.labelKey: string(w.GetUID())})
+
+		return append(objs, sm), nil
+	}
+}
+
+// hasServiceMonitor returns true if the supplied objects include the
+// ServiceMonitor of the supplied workload.
+func hasServiceMonitor(w resource.Workload, objs []resource.Object) bool {
+	for _, o := range objs {
+		if o.GetObjectKind().GroupVersionKind() == ServiceMonitorGroupVersionKind && o.GetName() == w.GetName()+ServiceMonitorNameSuffix {
+			return true
+		}
+	}
+	return false
+}
+
+// exposesPort returns true if the supplied objects include a Service labelled
+// as belonging to the supplied workload that exposes a port with the supplied
+// name.
+func exposesPort(labelKey string, w resource.Workload, name string, objs []resource.Object) bool {
+	for _, o := range objs {
+		s, ok := o.(*corev1.Service)
+		if !ok || s.GetLabels()[labelKey] != string(w.GetUID()) {
+			continue
+		}
+		for _, p := range s.Spec.Ports {
+			if p.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
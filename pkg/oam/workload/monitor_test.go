@@ -0,0 +1,166 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ workload.TranslationWrapper = ServiceMonitorInjector
+
+func serviceMonitor(port, path string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "monitoring.coreos.com/v1",
+		"kind":       "ServiceMonitor",
+		"metadata": map[string]interface{}{
+			"name": workloadName + ServiceMonitorNameSuffix,
+			"labels": map[string]interface{}{
+				LabelKey: workloadUID,
+			},
+		},
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{
+					LabelKey: workloadUID,
+				},
+			},
+			"endpoints": []interface{}{
+				map[string]interface{}{
+					"port": port,
+					"path": path,
+				},
+			},
+		},
+	}}
+}
+
+func TestServiceMonitorInjector(t *testing.T) {
+	metricsPort := fmt.Sprintf("%s-%d", portName, 9090)
+
+	type args struct {
+		w resource.Workload
+		o []resource.Object
+	}
+
+	type want struct {
+		result []resource.Object
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoMetricsPort": {
+			reason: "No ServiceMonitor should be injected for a workload that does not name a metrics port.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(9090)), service(sWithContainerPort(9090))},
+			},
+			want: want{result: []resource.Object{deployment(dmWithContainerPorts(9090)), service(sWithContainerPort(9090))}},
+		},
+		"SuccessfulDefaultPath": {
+			reason: "A ServiceMonitor scraping /metrics from the named port should be injected.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        workloadName,
+						Namespace:   workloadNamespace,
+						UID:         types.UID(workloadUID),
+						Annotations: map[string]string{AnnotationKeyMetricsPort: metricsPort},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(8080, 9090)), service(sWithContainerPort(8080), sWithContainerPort(9090))},
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithContainerPorts(8080, 9090)),
+				service(sWithContainerPort(8080), sWithContainerPort(9090)),
+				serviceMonitor(metricsPort, "/metrics"),
+			}},
+		},
+		"SuccessfulCustomPath": {
+			reason: "A ServiceMonitor scraping the configured path from the named port should be injected.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+						Annotations: map[string]string{
+							AnnotationKeyMetricsPort: metricsPort,
+							AnnotationKeyMetricsPath: "/stats/prometheus",
+						},
+					},
+				},
+				o: []resource.Object{service(sWithContainerPort(9090))},
+			},
+			want: want{result: []resource.Object{
+				service(sWithContainerPort(9090)),
+				serviceMonitor(metricsPort, "/stats/prometheus"),
+			}},
+		},
+		"ErrorNoSuchPort": {
+			reason: "An error should be returned if no Service exposes the named metrics port.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        workloadName,
+						Namespace:   workloadNamespace,
+						UID:         types.UID(workloadUID),
+						Annotations: map[string]string{AnnotationKeyMetricsPort: "metrics"},
+					},
+				},
+				o: []resource.Object{service(sWithContainerPort(8080))},
+			},
+			want: want{err: errors.Errorf(errFmtNoMetricsPort, "metrics")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := ServiceMonitorInjector(context.Background(), tc.args.w, tc.args.o)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nServiceMonitorInjector(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, r); diff != "" {
+				t.Errorf("\nReason: %s\nServiceMonitorInjector(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errNetworkScopeUID = "network scope must have a UID"
+)
+
+// LabelKeyNetworkScope is used to label the pods of each workload in a
+// network scope with the UID of that scope.
+const LabelKeyNetworkScope = "scope.oam.dev/network"
+
+// NetworkPolicyNameSuffix is appended to the name of a network scope in order
+// to derive the name of its NetworkPolicies.
+const NetworkPolicyNameSuffix = "-network-scope"
+
+var (
+	networkPolicyKind       = reflect.TypeOf(networkingv1.NetworkPolicy{}).Name()
+	networkPolicyAPIVersion = networkingv1.SchemeGroupVersion.String()
+)
+
+// dnsPort is the port on which cluster DNS is served.
+const dnsPort = 53
+
+// NewNetworkScopeLabeler returns a TranslationWrapper that labels the pod
+// template of each translated object that manages pods with the UID of the
+// supplied network scope, using LabelKeyNetworkScope. It should be run for
+// each workload in the scope, before KubeAppWrapper, so that the pods of the
+// workload are selected by the scope's NetworkPolicies.
+func NewNetworkScopeLabeler(scope metav1.Object) workload.TranslationWrapper {
+	return func(ctx context.Context, w resource.Workload, objs []resource.Object) ([]resource.Object, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if scope.GetUID() == "" {
+			return nil, errors.New(errNetworkScopeUID)
+		}
+
+		for _, o := range objs {
+			t := PodTemplate(o)
+			if t == nil {
+				continue
+			}
+			l := t.GetLabels()
+			if l == nil {
+				l = map[string]string{}
+			}
+			l[LabelKeyNetworkScope] = string(scope.GetUID())
+			t.SetLabels(l)
+		}
+		return objs, nil
+	}
+}
+
+// NetworkPolicies returns the NetworkPolicies that enforce the supplied
+// network scope. One NetworkPolicy is produced for each namespace that
+// contains one of the supplied workloads, ordered by namespace. Each policy
+// selects the pods labelled with the scope's UID using LabelKeyNetworkScope,
+// allows ingress from and egress to pods in any namespace that carry the same
+// label, and denies all other traffic except egress to DNS. The pods of each
+// workload must be labelled using NewNetworkScopeLabeler.
+func NetworkPolicies(scope metav1.Object, ws []resource.Workload) ([]*networkingv1.NetworkPolicy, error) {
+	if scope.GetUID() == "" {
+		return nil, errors.New(errNetworkScopeUID)
+	}
+
+	seen := map[string]bool{}
+	namespaces := make([]string, 0, len(ws))
+	for _, w := range ws {
+		if seen[w.GetNamespace()] {
+			continue
+		}
+		seen[w.GetNamespace()] = true
+		namespaces = append(namespaces, w.GetNamespace())
+	}
+	sort.Strings(namespaces)
+
+	name := truncate(scope.GetName(), validation.DNS1123SubdomainMaxLength-len(NetworkPolicyNameSuffix)) + NetworkPolicyNameSuffix
+	labels := map[string]string{LabelKeyNetworkScope: string(scope.GetUID())}
+
+	policies := make([]*networkingv1.NetworkPolicy, 0, len(namespaces))
+	for _, ns := range namespaces {
+		policies = append(policies, networkPolicy(name, ns, labels))
+	}
+	return policies, nil
+}
+
+// networkPolicy returns a NetworkPolicy that restricts traffic to and from
+// pods with the supplied labels to other pods with the same labels.
+func networkPolicy(name, namespace string, labels map[string]string) *networkingv1.NetworkPolicy {
+	sel := metav1.LabelSelector{MatchLabels: labels}
+	peers := []networkingv1.NetworkPolicyPeer{{
+		PodSelector:       sel.DeepCopy(),
+		NamespaceSelector: &metav1.LabelSelector{},
+	}}
+
+	udp, tcp := corev1.ProtocolUDP, corev1.ProtocolTCP
+	dns := intstr.FromInt(dnsPort)
+
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       networkPolicyKind,
+			APIVersion: networkPolicyAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: sel,
+			Ingress:     []networkingv1.NetworkPolicyIngressRule{{From: peers}},
+			Egress: []networkingv1.NetworkPolicyEgressRule{
+				{To: peers},
+				{Ports: []networkingv1.NetworkPolicyPort{
+					{Protocol: &udp, Port: &dns},
+					{Protocol: &tcp, Port: &dns},
+				}},
+			},
+			PolicyTypes: []networkingv1.PolicyType{
+				networkingv1.PolicyTypeIngress,
+				networkingv1.PolicyTypeEgress,
+			},
+		},
+	}
+}
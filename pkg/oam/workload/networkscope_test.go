@@ -0,0 +1,163 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var (
+	scopeName = "cool-scope"
+	scopeUID  = "a-very-unique-scope"
+)
+
+func networkPolicyIn(ns string) *networkingv1.NetworkPolicy {
+	labels := map[string]string{LabelKeyNetworkScope: scopeUID}
+	peers := []networkingv1.NetworkPolicyPeer{{
+		PodSelector:       &metav1.LabelSelector{MatchLabels: labels},
+		NamespaceSelector: &metav1.LabelSelector{},
+	}}
+	udp, tcp := corev1.ProtocolUDP, corev1.ProtocolTCP
+	dns := intstr.FromInt(53)
+
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       networkPolicyKind,
+			APIVersion: networkPolicyAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      scopeName + NetworkPolicyNameSuffix,
+			Namespace: ns,
+			Labels:    labels,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: labels},
+			Ingress:     []networkingv1.NetworkPolicyIngressRule{{From: peers}},
+			Egress: []networkingv1.NetworkPolicyEgressRule{
+				{To: peers},
+				{Ports: []networkingv1.NetworkPolicyPort{
+					{Protocol: &udp, Port: &dns},
+					{Protocol: &tcp, Port: &dns},
+				}},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		},
+	}
+}
+
+func TestNetworkPolicies(t *testing.T) {
+	workloadIn := func(ns string) resource.Workload {
+		return &fake.Workload{ObjectMeta: metav1.ObjectMeta{Name: workloadName, Namespace: ns, UID: types.UID(workloadUID)}}
+	}
+
+	type args struct {
+		scope metav1.Object
+		ws    []resource.Workload
+	}
+
+	type want struct {
+		policies []*networkingv1.NetworkPolicy
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoUID": {
+			reason: "A network scope without a UID should return an error.",
+			args: args{
+				scope: &metav1.ObjectMeta{Name: scopeName},
+				ws:    []resource.Workload{workloadIn(workloadNamespace)},
+			},
+			want: want{err: errors.New(errNetworkScopeUID)},
+		},
+		"NoWorkloads": {
+			reason: "A network scope without workloads should produce no NetworkPolicies.",
+			args: args{
+				scope: &metav1.ObjectMeta{Name: scopeName, UID: types.UID(scopeUID)},
+			},
+			want: want{policies: []*networkingv1.NetworkPolicy{}},
+		},
+		"OnePolicyPerNamespace": {
+			reason: "One NetworkPolicy should be produced for each namespace containing a workload, ordered by namespace.",
+			args: args{
+				scope: &metav1.ObjectMeta{Name: scopeName, UID: types.UID(scopeUID)},
+				ws: []resource.Workload{
+					workloadIn("b-namespace"),
+					workloadIn("a-namespace"),
+					workloadIn("b-namespace"),
+				},
+			},
+			want: want{policies: []*networkingv1.NetworkPolicy{
+				networkPolicyIn("a-namespace"),
+				networkPolicyIn("b-namespace"),
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NetworkPolicies(tc.args.scope, tc.args.ws)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nNetworkPolicies(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.policies, got); diff != "" {
+				t.Errorf("\nReason: %s\nNetworkPolicies(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNetworkScopeLabeler(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+	}
+	scope := &metav1.ObjectMeta{Name: scopeName, UID: types.UID(scopeUID)}
+
+	objs, err := NewNetworkScopeLabeler(scope)(context.Background(), w, []resource.Object{deployment(), configMap()})
+	if err != nil {
+		t.Fatalf("NewNetworkScopeLabeler(...): %s", err)
+	}
+
+	want := map[string]string{LabelKey: workloadUID, LabelKeyNetworkScope: scopeUID}
+	if diff := cmp.Diff(want, objs[0].(*appsv1.Deployment).Spec.Template.GetLabels()); diff != "" {
+		t.Errorf("NewNetworkScopeLabeler(...): -want pod template labels, +got pod template labels:\n%s", diff)
+	}
+	if diff := cmp.Diff(configMap(), objs[1]); diff != "" {
+		t.Errorf("NewNetworkScopeLabeler(...): -want unchanged ConfigMap, +got ConfigMap:\n%s", diff)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const errUnknownWorkloadKind = "cannot reference a workload of unknown kind"
+
+// AddControllerReference makes the supplied workload the controller of each of
+// the supplied objects, so that they are garbage collected when the workload
+// is deleted. The workload cannot be deleted in the foreground until the
+// objects are gone. Any existing owner reference to the workload is replaced.
+// The workload's kind must be known, i.e. its TypeMeta must be populated.
+func AddControllerReference(w resource.Workload, objs ...resource.Object) error {
+	gvk := w.GetObjectKind().GroupVersionKind()
+	if gvk.Kind == "" {
+		return errors.New(errUnknownWorkloadKind)
+	}
+	ref := *metav1.NewControllerRef(w, gvk)
+
+	for _, o := range objs {
+		refs := o.GetOwnerReferences()
+		replaced := false
+		for i := range refs {
+			if refs[i].UID == ref.UID {
+				refs[i] = ref
+				replaced = true
+			}
+		}
+		if !replaced {
+			refs = append(refs, ref)
+		}
+		o.SetOwnerReferences(refs)
+	}
+	return nil
+}
+
+// ControllerReferenceWrapper makes the workload the controller of each
+// translated object. It should usually run after KubeAppWrapper, so that only
+// the KubernetesApplication references the workload. Objects wrapped in a
+// KubernetesApplication are applied to a remote cluster, where an owner
+// reference to the workload would cause them to be garbage collected.
+func ControllerReferenceWrapper(ctx context.Context, w resource.Workload, objs []resource.Object) ([]resource.Object, error) {
+	if err := AddControllerReference(w, objs...); err != nil {
+		return nil, err
+	}
+	return objs, nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	oamv1alpha2 "github.com/crossplane/crossplane/apis/oam/v1alpha2"
+)
+
+var _ workload.TranslationWrapper = ControllerReferenceWrapper
+
+func TestControllerReferenceWrapper(t *testing.T) {
+	w := &oamv1alpha2.ContainerizedWorkload{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: oamv1alpha2.SchemeGroupVersion.String(),
+			Kind:       oamv1alpha2.ContainerizedWorkloadKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+	}
+
+	controller := true
+	ref := metav1.OwnerReference{
+		APIVersion:         oamv1alpha2.SchemeGroupVersion.String(),
+		Kind:               oamv1alpha2.ContainerizedWorkloadKind,
+		Name:               workloadName,
+		UID:                types.UID(workloadUID),
+		Controller:         &controller,
+		BlockOwnerDeletion: &controller,
+	}
+	other := metav1.OwnerReference{
+		APIVersion: "example.org/v1",
+		Kind:       "Other",
+		Name:       "other",
+		UID:        types.UID("some-other-identifier"),
+	}
+
+	withOwners := func(o resource.Object, refs ...metav1.OwnerReference) resource.Object {
+		o.SetOwnerReferences(refs)
+		return o
+	}
+
+	type args struct {
+		w resource.Workload
+		o []resource.Object
+	}
+
+	type want struct {
+		result []resource.Object
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrorUnknownKind": {
+			reason: "A workload of unknown kind should return an error.",
+			args: args{
+				w: &fake.Workload{},
+				o: []resource.Object{deployment()},
+			},
+			want: want{err: errors.New(errUnknownWorkloadKind)},
+		},
+		"Successful": {
+			reason: "Each object should be controlled by the workload.",
+			args: args{
+				w: w,
+				o: []resource.Object{deployment(), configMap()},
+			},
+			want: want{result: []resource.Object{
+				withOwners(deployment(), ref),
+				withOwners(configMap(), ref),
+			}},
+		},
+		"SuccessfulExistingOwners": {
+			reason: "Existing references to other owners should be kept, and existing references to the workload replaced.",
+			args: args{
+				w: w,
+				o: []resource.Object{withOwners(deployment(), other, metav1.OwnerReference{UID: types.UID(workloadUID)})},
+			},
+			want: want{result: []resource.Object{withOwners(deployment(), other, ref)}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := ControllerReferenceWrapper(context.Background(), tc.args.w, tc.args.o)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nControllerReferenceWrapper(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, r); diff != "" {
+				t.Errorf("\nReason: %s\nControllerReferenceWrapper(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
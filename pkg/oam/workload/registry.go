@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
+)
+
+const (
+	errEmptyWrapperName     = "translation wrapper name must not be empty"
+	errFmtNilWrapper        = "translation wrapper %q must not be nil"
+	errFmtWrapperRegistered = "translation wrapper %q is already registered"
+)
+
+// Names under which the built in TranslationWrappers are registered.
+const (
+	WrapperNameKubeApp = "KubeAppWrapper"
+	WrapperNameService = "ServiceInjector"
+	WrapperNameNoop    = "NoopWrapper"
+)
+
+var registry = struct {
+	sync.RWMutex
+	wrappers map[string]workload.TranslationWrapper
+}{
+	wrappers: map[string]workload.TranslationWrapper{
+		WrapperNameKubeApp: KubeAppWrapper,
+		WrapperNameService: ServiceInjector,
+		WrapperNameNoop:    NoopWrapper,
+	},
+}
+
+// RegisterWrapper registers the supplied TranslationWrapper under the
+// supplied name, so that translation pipelines may be composed from
+// configuration using LookupWrapper. It returns an error if the name is empty
+// or already registered. KubeAppWrapper, ServiceInjector, and NoopWrapper are
+// registered by default, under their own names.
+func RegisterWrapper(name string, w workload.TranslationWrapper) error {
+	if name == "" {
+		return errors.New(errEmptyWrapperName)
+	}
+	if w == nil {
+		return errors.Errorf(errFmtNilWrapper, name)
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.wrappers[name]; ok {
+		return errors.Errorf(errFmtWrapperRegistered, name)
+	}
+	registry.wrappers[name] = w
+	return nil
+}
+
+// LookupWrapper returns the TranslationWrapper registered under the supplied
+// name, and whether one was registered.
+func LookupWrapper(name string) (workload.TranslationWrapper, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	w, ok := registry.wrappers[name]
+	return w, ok
+}
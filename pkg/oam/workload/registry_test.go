@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestRegisterWrapper(t *testing.T) {
+	cool := func(ctx context.Context, w resource.Workload, objs []resource.Object) ([]resource.Object, error) {
+		return append(objs, configMap()), nil
+	}
+
+	// Unregister the wrapper registered by this test so that it may run
+	// more than once.
+	defer func() {
+		registry.Lock()
+		delete(registry.wrappers, "TestRegisterWrapperCool")
+		registry.Unlock()
+	}()
+
+	type args struct {
+		name string
+		w    workload.TranslationWrapper
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"Successful": {
+			reason: "A wrapper should be registered under a new name.",
+			args:   args{name: "TestRegisterWrapperCool", w: cool},
+		},
+		"EmptyName": {
+			reason: "A wrapper may not be registered without a name.",
+			args:   args{w: cool},
+			want:   errors.New(errEmptyWrapperName),
+		},
+		"NilWrapper": {
+			reason: "A nil wrapper may not be registered.",
+			args:   args{name: "TestRegisterWrapperNil"},
+			want:   errors.Errorf(errFmtNilWrapper, "TestRegisterWrapperNil"),
+		},
+		"DuplicateName": {
+			reason: "A wrapper may not be registered under the name of a built in wrapper.",
+			args:   args{name: WrapperNameKubeApp, w: cool},
+			want:   errors.Errorf(errFmtWrapperRegistered, WrapperNameKubeApp),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := RegisterWrapper(tc.args.name, tc.args.w)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nRegisterWrapper(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+
+	w, ok := LookupWrapper("TestRegisterWrapperCool")
+	if !ok {
+		t.Fatalf("LookupWrapper(...): a registered wrapper should be found")
+	}
+	got, err := w(context.Background(), &fake.Workload{}, nil)
+	if err != nil {
+		t.Fatalf("LookupWrapper(...): %s", err)
+	}
+	if diff := cmp.Diff([]resource.Object{configMap()}, got); diff != "" {
+		t.Errorf("LookupWrapper(...): the registered wrapper should be returned: -want, +got:\n%s", diff)
+	}
+}
+
+func TestLookupWrapper(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		name   string
+		want   bool
+	}{
+		"KubeAppWrapper": {
+			reason: "KubeAppWrapper should be registered by default.",
+			name:   WrapperNameKubeApp,
+			want:   true,
+		},
+		"ServiceInjector": {
+			reason: "ServiceInjector should be registered by default.",
+			name:   WrapperNameService,
+			want:   true,
+		},
+		"NoopWrapper": {
+			reason: "NoopWrapper should be registered by default.",
+			name:   WrapperNameNoop,
+			want:   true,
+		},
+		"Unknown": {
+			reason: "An unregistered name should not be found.",
+			name:   "CoolWrapper",
+			want:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, ok := LookupWrapper(tc.name)
+			if diff := cmp.Diff(tc.want, ok); diff != "" {
+				t.Errorf("\nReason: %s\nLookupWrapper(...): -want found, +got found:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errRenderTranslate   = "unable to translate workload"
+	errFmtRenderNoKind   = "unable to render object %q: its apiVersion and kind must be set"
+	errFmtRenderMarshal  = "unable to render %s %q"
+	renderDocumentPrefix = "---\n"
+)
+
+// RenderTranslation translates the supplied workload using the supplied
+// TranslateFn, runs the resulting objects through the supplied wrappers in
+// order, and renders the objects they produce as a multi-document YAML
+// string. Documents are ordered by the kind and then name of the object they
+// render, so a workload is always rendered identically. Every object must
+// have its apiVersion and kind set. RenderTranslation is intended for
+// previewing the translation of a workload; nothing is applied.
+func RenderTranslation(ctx context.Context, w resource.Workload, fn workload.TranslateFn, wrappers ...workload.TranslationWrapper) (string, error) {
+	objs, err := fn(ctx, w)
+	if err != nil {
+		return "", errors.Wrap(err, errRenderTranslate)
+	}
+	objs, err = NewTranslator(WithWrappers(wrappers...)).Wrap(ctx, w, objs)
+	if err != nil {
+		return "", err
+	}
+
+	b := &strings.Builder{}
+	for _, o := range sortedByKindAndName(objs) {
+		gvk := o.GetObjectKind().GroupVersionKind()
+		if gvk.Kind == "" || gvk.Version == "" {
+			return "", errors.Errorf(errFmtRenderNoKind, o.GetName())
+		}
+		y, err := yaml.Marshal(o)
+		if err != nil {
+			return "", errors.Wrapf(err, errFmtRenderMarshal, gvk.Kind, o.GetName())
+		}
+		b.WriteString(renderDocumentPrefix)
+		b.Write(y)
+	}
+	return b.String(), nil
+}
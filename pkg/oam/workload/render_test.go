@@ -0,0 +1,175 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+// translateTo returns a TranslateFn that always returns the supplied objects.
+func translateTo(objs ...resource.Object) workload.TranslateFn {
+	return func(_ context.Context, _ resource.Workload) ([]resource.Object, error) {
+		return objs, nil
+	}
+}
+
+func TestRenderTranslation(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+	}
+
+	noKind := configMap()
+	noKind.TypeMeta = metav1.TypeMeta{}
+
+	type args struct {
+		fn       workload.TranslateFn
+		wrappers []workload.TranslationWrapper
+	}
+	type want struct {
+		kinds []string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrorTranslate": {
+			reason: "Errors translating the workload should be returned",
+			args: args{
+				fn: func(_ context.Context, _ resource.Workload) ([]resource.Object, error) {
+					return nil, errBoom
+				},
+			},
+			want: want{err: errors.Wrap(errBoom, errRenderTranslate)},
+		},
+		"ErrorWrapper": {
+			reason: "Errors returned by a wrapper should be returned",
+			args: args{
+				fn:       translateTo(configMap()),
+				wrappers: []workload.TranslationWrapper{errorWrapper},
+			},
+			want: want{err: errBoom},
+		},
+		"ErrorNoKind": {
+			reason: "Objects without a kind should not be rendered",
+			args: args{
+				fn: translateTo(noKind),
+			},
+			want: want{err: errors.Errorf(errFmtRenderNoKind, workloadName)},
+		},
+		"NoObjects": {
+			reason: "A translation that produces no objects should render no documents",
+			args: args{
+				fn: translateTo(),
+			},
+			want: want{},
+		},
+		"OrderedByKind": {
+			reason: "Documents should be ordered by kind regardless of the order in which objects were produced",
+			args: args{
+				fn:       translateTo(service(), secret()),
+				wrappers: []workload.TranslationWrapper{appendWrapper(configMap())},
+			},
+			want: want{kinds: []string{"ConfigMap", "Secret", "Service"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := RenderTranslation(context.Background(), w, tc.args.fn, tc.args.wrappers...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRenderTranslation(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			kinds := []string{}
+			for _, doc := range splitDocuments(got) {
+				m := &metav1.TypeMeta{}
+				if err := yaml.Unmarshal([]byte(doc), m); err != nil {
+					t.Fatalf("yaml.Unmarshal(...): %s", err)
+				}
+				kinds = append(kinds, m.Kind)
+			}
+			if diff := cmp.Diff(tc.want.kinds, kinds, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nRenderTranslation(...): -want kinds, +got kinds:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRenderTranslationRoundTrip(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+	}
+
+	want := []resource.Object{configMap(), secret(), service(sWithContainerPort(8080))}
+
+	rendered, err := RenderTranslation(context.Background(), w, translateTo(service(sWithContainerPort(8080)), secret(), configMap()))
+	if err != nil {
+		t.Fatalf("RenderTranslation(...): %s", err)
+	}
+
+	docs := splitDocuments(rendered)
+	got := []resource.Object{&corev1.ConfigMap{}, &corev1.Secret{}, &corev1.Service{}}
+	if len(docs) != len(got) {
+		t.Fatalf("RenderTranslation(...): want %d documents, got %d:\n%s", len(got), len(docs), rendered)
+	}
+	for i := range docs {
+		if err := yaml.Unmarshal([]byte(docs[i]), got[i]); err != nil {
+			t.Fatalf("yaml.Unmarshal(...): %s", err)
+		}
+	}
+
+	if diff := cmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("RenderTranslation(...): -want, +got:\n%s", diff)
+	}
+}
+
+// splitDocuments splits a multi-document YAML string into its documents.
+func splitDocuments(s string) []string {
+	docs := []string{}
+	for _, d := range strings.Split(s, renderDocumentPrefix) {
+		if strings.TrimSpace(d) != "" {
+			docs = append(docs, d)
+		}
+	}
+	return docs
+}
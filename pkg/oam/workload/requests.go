@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	kresource "k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const reasonFmtDefaultedRequest = "no %s request was set; a request of %s was injected"
+
+// DefaultRequests are the compute resource requests injected by
+// RequestDefaulter.
+var DefaultRequests = corev1.ResourceList{
+	corev1.ResourceCPU:    kresource.MustParse("100m"),
+	corev1.ResourceMemory: kresource.MustParse("128Mi"),
+}
+
+// RequestDefaulter injects the DefaultRequests into each container of each
+// translated object that manages pods, unless the container already requests
+// or limits the resource. Clusters that enforce a ResourceQuota reject pods
+// whose containers make no requests. A Warning is recorded for each injected
+// request. RequestDefaulter should be run before KubeAppWrapper in order for
+// the wrapped objects to be defaulted.
+func RequestDefaulter(ctx context.Context, w resource.Workload, objs []resource.Object) ([]resource.Object, error) {
+	return NewRequestDefaulter(DefaultRequests)(ctx, w, objs)
+}
+
+// NewRequestDefaulter returns a TranslationWrapper that behaves like
+// RequestDefaulter, but that injects the supplied requests. No request is
+// injected for a resource the container limits; Kubernetes defaults such a
+// request to the limit, and injecting a lower one would change the pod's
+// quality of service class.
+func NewRequestDefaulter(defaults corev1.ResourceList) workload.TranslationWrapper {
+	names := make([]string, 0, len(defaults))
+	for n := range defaults {
+		names = append(names, string(n))
+	}
+	sort.Strings(names)
+
+	return func(ctx context.Context, w resource.Workload, objs []resource.Object) ([]resource.Object, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		for _, o := range objs {
+			t := PodTemplate(o)
+			if t == nil {
+				continue
+			}
+			ps := &t.Spec
+			for i := range ps.InitContainers {
+				defaultRequests(ctx, &ps.InitContainers[i], names, defaults)
+			}
+			for i := range ps.Containers {
+				defaultRequests(ctx, &ps.Containers[i], names, defaults)
+			}
+		}
+		return objs, nil
+	}
+}
+
+// defaultRequests injects the supplied default requests, in the order of the
+// supplied resource names, into the supplied container. Resources that the
+// container already requests or limits are skipped.
+func defaultRequests(ctx context.Context, c *corev1.Container, names []string, defaults corev1.ResourceList) {
+	for _, n := range names {
+		name := corev1.ResourceName(n)
+		if _, ok := c.Resources.Requests[name]; ok {
+			continue
+		}
+		if _, ok := c.Resources.Limits[name]; ok {
+			continue
+		}
+
+		q := defaults[name].DeepCopy()
+		if c.Resources.Requests == nil {
+			c.Resources.Requests = corev1.ResourceList{}
+		}
+		c.Resources.Requests[name] = q
+		RecordWarning(ctx, Warning{
+			Field:  fmt.Sprintf("containers[%s].resources.requests.%s", c.Name, name),
+			Reason: fmt.Sprintf(reasonFmtDefaultedRequest, name, q.String()),
+		})
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kresource "k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+)
+
+var _ workload.TranslationWrapper = RequestDefaulter
+
+func TestRequestDefaulter(t *testing.T) {
+	withResources := func(r corev1.ResourceRequirements) deploymentModifier {
+		return func(d *appsv1.Deployment) {
+			d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{Name: containerName, Resources: r})
+		}
+	}
+
+	field := func(name corev1.ResourceName) string {
+		return fmt.Sprintf("containers[%s].resources.requests.%s", containerName, name)
+	}
+
+	type want struct {
+		resources corev1.ResourceRequirements
+		warnings  []Warning
+	}
+
+	cases := map[string]struct {
+		reason string
+		r      corev1.ResourceRequirements
+		want   want
+	}{
+		"NoRequests": {
+			reason: "Default requests should be injected into a container that makes no requests.",
+			r:      corev1.ResourceRequirements{},
+			want: want{
+				resources: corev1.ResourceRequirements{Requests: DefaultRequests},
+				warnings: []Warning{
+					{Field: field(corev1.ResourceCPU), Reason: fmt.Sprintf(reasonFmtDefaultedRequest, corev1.ResourceCPU, "100m")},
+					{Field: field(corev1.ResourceMemory), Reason: fmt.Sprintf(reasonFmtDefaultedRequest, corev1.ResourceMemory, "128Mi")},
+				},
+			},
+		},
+		"ExistingRequest": {
+			reason: "An existing request should not be replaced.",
+			r: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU: kresource.MustParse("2"),
+			}},
+			want: want{
+				resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    kresource.MustParse("2"),
+					corev1.ResourceMemory: kresource.MustParse("128Mi"),
+				}},
+				warnings: []Warning{
+					{Field: field(corev1.ResourceMemory), Reason: fmt.Sprintf(reasonFmtDefaultedRequest, corev1.ResourceMemory, "128Mi")},
+				},
+			},
+		},
+		"LimitOnly": {
+			reason: "No request should be injected for a resource the container limits, since Kubernetes defaults the request to the limit.",
+			r: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    kresource.MustParse("1"),
+				corev1.ResourceMemory: kresource.MustParse("64Mi"),
+			}},
+			want: want{
+				resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    kresource.MustParse("1"),
+					corev1.ResourceMemory: kresource.MustParse("64Mi"),
+				}},
+			},
+		},
+		"PartialLimit": {
+			reason: "Default requests should be injected only for resources the container does not limit.",
+			r: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+				corev1.ResourceMemory: kresource.MustParse("64Mi"),
+			}},
+			want: want{
+				resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU: kresource.MustParse("100m"),
+					},
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: kresource.MustParse("64Mi"),
+					},
+				},
+				warnings: []Warning{
+					{Field: field(corev1.ResourceCPU), Reason: fmt.Sprintf(reasonFmtDefaultedRequest, corev1.ResourceCPU, "100m")},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &WarningRecorder{}
+			objs, err := RequestDefaulter(WithWarningRecorder(context.Background(), r), &fake.Workload{}, []resource.Object{deployment(withResources(tc.r))})
+			if err != nil {
+				t.Fatalf("RequestDefaulter(...): %s", err)
+			}
+
+			got := objs[0].(*appsv1.Deployment).Spec.Template.Spec.Containers[0].Resources
+			if diff := cmp.Diff(tc.want.resources, got); diff != "" {
+				t.Errorf("\nReason: %s\nRequestDefaulter(...): -want resources, +got resources:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.warnings, r.Warnings()); diff != "" {
+				t.Errorf("\nReason: %s\nRequestDefaulter(...): -want warnings, +got warnings:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Sanitize returns a copy of each supplied object without the fields that
+// are set by the API server rather than by a translation, i.e. its status,
+// managed fields, creation timestamp, and resource version. Translations that
+// start from an existing object can otherwise carry these fields into the
+// objects they produce, causing noisy or failed applies. It should be run
+// before KubeAppWrapper, so that wrapped objects are sanitized too.
+func Sanitize(ctx context.Context, w resource.Workload, objs []resource.Object) ([]resource.Object, error) {
+	if objs == nil {
+		return nil, nil
+	}
+
+	out := make([]resource.Object, 0, len(objs))
+	for _, o := range objs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		out = append(out, sanitize(o))
+	}
+	return out, nil
+}
+
+// sanitize returns a copy of the supplied object without its status, managed
+// fields, creation timestamp, or resource version.
+func sanitize(o resource.Object) resource.Object {
+	s := o.DeepCopyObject().(resource.Object)
+	s.SetManagedFields(nil)
+	s.SetCreationTimestamp(metav1.Time{})
+	s.SetResourceVersion("")
+
+	if u, ok := s.(*unstructured.Unstructured); ok {
+		unstructured.RemoveNestedField(u.Object, "status")
+		return u
+	}
+
+	// Typed objects conventionally store their status in a field named Status.
+	v := reflect.ValueOf(s)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return s
+	}
+	if f := v.Elem().FieldByName("Status"); f.IsValid() && f.CanSet() {
+		f.Set(reflect.Zero(f.Type()))
+	}
+	return s
+}
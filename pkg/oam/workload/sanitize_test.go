@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ workload.TranslationWrapper = Sanitize
+
+func TestSanitize(t *testing.T) {
+	serverSet := func(o metav1.Object) {
+		o.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply}})
+		o.SetCreationTimestamp(metav1.Now())
+		o.SetResourceVersion("42")
+	}
+
+	withStatus := func(d *appsv1.Deployment) {
+		serverSet(d)
+		d.Status = appsv1.DeploymentStatus{Replicas: 3, ReadyReplicas: 2, ObservedGeneration: 7}
+	}
+
+	sanitized := func(d *appsv1.Deployment) {
+		d.SetCreationTimestamp(metav1.Time{})
+	}
+
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.org/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]interface{}{"name": "cool"},
+		"spec":       map[string]interface{}{"size": "large"},
+		"status":     map[string]interface{}{"phase": "Ready"},
+	}}
+	serverSet(u)
+
+	wantU := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.org/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]interface{}{"name": "cool"},
+		"spec":       map[string]interface{}{"size": "large"},
+	}}
+
+	type want struct {
+		result []resource.Object
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		objs   []resource.Object
+		want   want
+	}{
+		"Nil": {
+			reason: "Sanitizing nil objects should return nil.",
+		},
+		"Typed": {
+			reason: "The status and server set metadata of a typed object should be cleared.",
+			objs:   []resource.Object{deployment(withStatus)},
+			want:   want{result: []resource.Object{deployment(sanitized)}},
+		},
+		"Unstructured": {
+			reason: "The status and server set metadata of an unstructured object should be cleared.",
+			objs:   []resource.Object{u},
+			want:   want{result: []resource.Object{wantU}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Sanitize(context.Background(), nil, tc.objs)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nSanitize(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\nReason: %s\nSanitize(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+
+	// The supplied objects should not be modified.
+	if _, ok, _ := unstructured.NestedMap(u.Object, "status"); !ok {
+		t.Errorf("Sanitize(...): the supplied object's status was modified")
+	}
+}
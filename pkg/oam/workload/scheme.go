@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errFmtUnknownObject = "%s %q is not known to the scheme"
+	errFmtEncodeObject  = "cannot encode %s %q using the scheme"
+	errFmtDecodeObject  = "cannot decode %s %q using the scheme"
+)
+
+// SchemeValidator returns a TranslationWrapper that round-trips each
+// translated object through JSON using the supplied scheme, and returns an
+// error identifying the first object that the scheme cannot encode or
+// decode. This catches objects whose kinds were not registered with the
+// scheme before they are applied. Objects are returned unchanged.
+func SchemeValidator(scheme *runtime.Scheme) workload.TranslationWrapper {
+	s := json.NewSerializerWithOptions(json.DefaultMetaFactory, scheme, scheme, json.SerializerOptions{})
+	return func(ctx context.Context, w resource.Workload, objs []resource.Object) ([]resource.Object, error) {
+		for _, o := range objs {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			gvks, _, err := scheme.ObjectKinds(o)
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtUnknownObject, kindOf(o), o.GetName())
+			}
+
+			// Typed objects may omit their TypeMeta, which the serializer
+			// requires in order to decode them.
+			c := o.DeepCopyObject()
+			c.GetObjectKind().SetGroupVersionKind(gvks[0])
+
+			b, err := runtime.Encode(s, c)
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtEncodeObject, kindOf(o), o.GetName())
+			}
+			if _, err := runtime.Decode(s, b); err != nil {
+				return nil, errors.Wrapf(err, errFmtDecodeObject, kindOf(o), o.GetName())
+			}
+		}
+		return objs, nil
+	}
+}
+
+// kindOf returns the kind of the supplied object, or the name of its Go type
+// if its kind is not set.
+func kindOf(o resource.Object) string {
+	if k := o.GetObjectKind().GroupVersionKind().Kind; k != "" {
+		return k
+	}
+	return reflect.TypeOf(o).Elem().Name()
+}
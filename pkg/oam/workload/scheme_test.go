@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestSchemeValidator(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+	}
+
+	// Only core types are registered, so Deployments are unknown.
+	s := runtime.NewScheme()
+	if err := corev1.AddToScheme(s); err != nil {
+		t.Fatalf("corev1.AddToScheme(...): %s", err)
+	}
+
+	_, _, kerr := s.ObjectKinds(deployment())
+
+	sm := &unstructured.Unstructured{}
+	sm.SetGroupVersionKind(ServiceMonitorGroupVersionKind)
+	sm.SetName(workloadName)
+	ser := json.NewSerializerWithOptions(json.DefaultMetaFactory, s, s, json.SerializerOptions{})
+	b, _ := runtime.Encode(ser, sm)
+	_, derr := runtime.Decode(ser, b)
+
+	type want struct {
+		objs []resource.Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		objs   []resource.Object
+		want   want
+	}{
+		"RegisteredTypes": {
+			reason: "Objects whose types are registered with the scheme should be returned unchanged.",
+			objs:   []resource.Object{configMap(), service()},
+			want:   want{objs: []resource.Object{configMap(), service()}},
+		},
+		"UnregisteredType": {
+			reason: "A typed object whose type is not registered with the scheme should return an error identifying it.",
+			objs:   []resource.Object{configMap(), deployment()},
+			want:   want{err: errors.Wrapf(kerr, errFmtUnknownObject, "Deployment", workloadName)},
+		},
+		"UnregisteredKind": {
+			reason: "An unstructured object whose kind is not registered with the scheme should return an error identifying it.",
+			objs:   []resource.Object{sm},
+			want:   want{err: errors.Wrapf(derr, errFmtDecodeObject, "ServiceMonitor", workloadName)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := SchemeValidator(s)(context.Background(), w, tc.objs)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nSchemeValidator(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, got); diff != "" {
+				t.Errorf("\nReason: %s\nSchemeValidator(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const errFmtObjectScope = "unable to determine whether %s %q is cluster scoped"
+
+// clusterScopedKinds are the well known kinds of object that are not
+// namespaced. They are used to determine the scope of an object when no
+// RESTMapper is configured, or the configured RESTMapper does not know the
+// object's kind.
+var clusterScopedKinds = map[schema.GroupKind]bool{
+	{Group: "", Kind: "Namespace"}:        true,
+	{Group: "", Kind: "Node"}:             true,
+	{Group: "", Kind: "PersistentVolume"}: true,
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"}:   true,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"}: true,
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:               true,
+	{Group: "apiregistration.k8s.io", Kind: "APIService"}:                           true,
+	{Group: "certificates.k8s.io", Kind: "CertificateSigningRequest"}:               true,
+	{Group: "node.k8s.io", Kind: "RuntimeClass"}:                                    true,
+	{Group: "policy", Kind: "PodSecurityPolicy"}:                                    true,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}:                       true,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}:                true,
+	{Group: "scheduling.k8s.io", Kind: "PriorityClass"}:                             true,
+	{Group: "storage.k8s.io", Kind: "CSIDriver"}:                                    true,
+	{Group: "storage.k8s.io", Kind: "CSINode"}:                                      true,
+	{Group: "storage.k8s.io", Kind: "StorageClass"}:                                 true,
+	{Group: "storage.k8s.io", Kind: "VolumeAttachment"}:                             true,
+}
+
+// clusterScoped returns true if the supplied object is not namespaced. The
+// supplied RESTMapper, if any, is consulted first. Kinds it does not know are
+// looked up in the set of well known cluster scoped kinds.
+func clusterScoped(m meta.RESTMapper, o resource.Object) (bool, error) {
+	gvk := o.GetObjectKind().GroupVersionKind()
+	if m != nil {
+		rm, err := m.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err == nil {
+			return rm.Scope.Name() == meta.RESTScopeNameRoot, nil
+		}
+		if !meta.IsNoMatchError(err) {
+			return false, errors.Wrapf(err, errFmtObjectScope, gvk.Kind, o.GetName())
+		}
+	}
+	return clusterScopedKinds[gvk.GroupKind()], nil
+}
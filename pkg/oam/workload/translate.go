@@ -18,25 +18,67 @@ package workload
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"net"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 
+	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	workloadv1alpha1 "github.com/crossplane/crossplane/apis/workload/v1alpha1"
 )
 
 const (
-	errWrapInKubeApp = "unable to wrap objects in KubernetesApplication"
+	errWrapInKubeApp        = "unable to wrap objects in KubernetesApplication"
+	errParseClusterSelector = "unable to parse cluster selector annotation"
+	errFmtMarshalObject     = "unable to marshal %s %q"
+	errFmtPatchObject       = "unable to patch %s %q"
+	errFmtInvalidTemplate   = "invalid resource template name %q: %s"
+	errFmtInvalidAppName    = "invalid KubernetesApplication name %q: %s"
+	errFmtInvalidTarget     = "invalid cluster target %q: must be of the form <namespace>/<name>"
+	errFmtTargetNamespace   = "cluster target %q must be in the workload's namespace %q"
+
+	errHeadlessServiceType   = "headless Services must be of type ClusterIP"
+	errFmtInvalidServiceName = "invalid Service name %q: %s"
+	errFmtInvalidServiceType = "invalid service type %q: must be one of ClusterIP, NodePort, or LoadBalancer"
+	errFmtInvalidNodePort    = "invalid node port mapping %q: must be of the form <port>:<node-port>"
+	errFmtNodePortRange      = "invalid node port %d for port %d: must be between %d and %d"
+	errFmtNodePortType       = "node ports cannot be set for Services of type %s"
+	errFmtNoServiceContainer = "cannot inject Service for container %q: no such container"
+	errFmtNoContainerPorts   = "cannot inject Service for container %q: container declares no ports"
+	errFmtInvalidPortName    = "invalid Service port name %q: %s"
+	errFmtDuplicatePortName  = "Service port name %q is used more than once"
+	errFmtInvalidAppProtocol = "invalid app protocol mapping %q: must be of the form <port>:<app-protocol>"
+	errFmtLoadBalancerType   = "load balancer settings cannot be set for Services of type %s"
+	errFmtInvalidLBIP        = "invalid load balancer IP address %q"
+	errFmtInvalidSourceRange = "invalid load balancer source range %q: must be a CIDR"
+	errFmtTrafficPolicyType  = "an external traffic policy cannot be set for Services of type %s"
+	errFmtInvalidTraffic     = "invalid external traffic policy %q: must be one of Local or Cluster"
+
+	errParseSessionAffinityTimeout   = "unable to parse session affinity timeout annotation"
+	errTimeoutWithoutClientIP        = "a session affinity timeout may only be set when session affinity is ClientIP"
+	errFmtInvalidSessionAffinity     = "invalid session affinity %q: must be one of ClientIP or None"
+	errFmtSessionAffinityTimeoutSecs = "invalid session affinity timeout %d: must be between 1 and %d seconds"
+
+	reasonFmtDuplicatePort       = "port %d/%s is already exposed as port %q; this port was dropped"
+	reasonLoadBalancerDowngraded = "the cluster does not support LoadBalancer Services; a NodePort Service was injected instead, and any load balancer IP or source ranges were dropped"
 )
 
 var (
@@ -44,101 +86,1220 @@ var (
 	serviceAPIVersion = corev1.SchemeGroupVersion.String()
 )
 
+// ErrNoTranslatableObject is returned by TranslationWrappers configured using
+// WithStrict when they are supplied no object they know how to handle.
+var ErrNoTranslatableObject = errors.New("no translatable object found")
+
 // LabelKey is the label applied to translated workload objects.
 const LabelKey = "workload.oam.crossplane.io"
 
+// ServiceNameSuffix is appended to the name of the object a Service is
+// injected for in order to derive the name of the Service.
+const ServiceNameSuffix = "-svc"
+
+// Annotations that may be set on a workload to configure the Service injected
+// by ServiceInjector.
+const (
+	// AnnotationKeyServiceType controls the type of the injected Service.
+	// Valid values are ClusterIP, NodePort, and LoadBalancer. A LoadBalancer
+	// Service is injected if it is not set.
+	AnnotationKeyServiceType = "core.oam.dev/service-type"
+
+	// AnnotationKeyHeadless causes a headless Service to be injected when set
+	// to "true". Headless Services are always of type ClusterIP.
+	AnnotationKeyHeadless = "core.oam.dev/headless"
+
+	// AnnotationKeyNoService prevents a Service from being injected when set
+	// to "true".
+	AnnotationKeyNoService = "core.oam.dev/no-service"
+
+	// AnnotationKeyServicePerContainer causes a Service to be injected for
+	// each container that declares ports, rather than one Service for all
+	// containers, when set to "true".
+	AnnotationKeyServicePerContainer = "core.oam.dev/service-per-container"
+
+	// AnnotationKeyServiceContainer names the container whose ports the
+	// injected Service exposes. The ports of all containers are exposed if it
+	// is not set. It takes precedence over AnnotationKeyServicePerContainer.
+	AnnotationKeyServiceContainer = "core.oam.dev/service-container"
+
+	// AnnotationKeyPrefixPortNames causes the ports of the injected Service
+	// to be named using ContainerPortNamer, i.e. prefixed with the name of
+	// their container, when set to "true". This keeps the names of ports
+	// aggregated from several containers distinct and meaningful. It takes
+	// precedence over any PortNamer configured using WithPortNamer.
+	AnnotationKeyPrefixPortNames = "core.oam.dev/prefix-port-names"
+
+	// AnnotationKeyNodePort is a comma separated list of <port>:<node-port>
+	// pairs, for example "80:30080,443:30443", that fix the node port of the
+	// injected Service's ports. It may only be set for NodePort and
+	// LoadBalancer Services. Ports that are not exposed by the Service are
+	// ignored.
+	AnnotationKeyNodePort = "core.oam.dev/node-port"
+
+	// AnnotationKeySessionAffinity controls the session affinity of the
+	// injected Service. Valid values are ClientIP and None. The Service's
+	// session affinity is left unset, and thus defaults to None, if it is not
+	// set.
+	AnnotationKeySessionAffinity = "core.oam.dev/session-affinity"
+
+	// AnnotationKeySessionAffinityTimeout is the number of seconds for which
+	// a ClientIP session sticks to a pod. It may only be set when
+	// AnnotationKeySessionAffinity is ClientIP.
+	AnnotationKeySessionAffinityTimeout = "core.oam.dev/session-affinity-timeout-seconds"
+
+	// AnnotationKeyLoadBalancerIP requests a specific IP address for a
+	// LoadBalancer Service, shared by all of its ports. Whether it is honoured
+	// depends on the cloud provider.
+	AnnotationKeyLoadBalancerIP = "core.oam.dev/load-balancer-ip"
+
+	// AnnotationKeyLoadBalancerSourceRanges is a comma separated list of
+	// CIDRs, for example "10.0.0.0/8,192.168.0.0/16", that restricts which
+	// clients may reach a LoadBalancer Service.
+	AnnotationKeyLoadBalancerSourceRanges = "core.oam.dev/load-balancer-source-ranges"
+
+	// AnnotationKeyExternalTrafficPolicy controls how a NodePort or
+	// LoadBalancer Service routes external traffic. Valid values are Local,
+	// which preserves the client source IP, and Cluster.
+	AnnotationKeyExternalTrafficPolicy = "core.oam.dev/external-traffic-policy"
+
+	// AnnotationKeyAppProtocol is a comma separated list of
+	// <port>:<app-protocol> pairs, for example "8080:grpc,9090:http", that
+	// set the application protocol of the injected Service's ports. It takes
+	// precedence over the protocol implied by a port's name. Ports that are
+	// not exposed by the Service are ignored.
+	AnnotationKeyAppProtocol = "core.oam.dev/app-protocol"
+)
+
+// AppProtocols maps well-known container port names to the application
+// protocol of the Service ports that expose them. Ports with other names have
+// no application protocol unless one is set using AnnotationKeyAppProtocol.
+// The mapping may be replaced using WithAppProtocols.
+var AppProtocols = map[string]string{
+	"http":  "http",
+	"http2": "http2",
+	"grpc":  "grpc",
+	"https": "https",
+}
+
+// maxSessionAffinityTimeout is the longest ClientIP session affinity timeout,
+// in seconds, that Kubernetes permits.
+const maxSessionAffinityTimeout = 24 * 60 * 60
+
+// The range of node ports a Service may request.
+const (
+	minNodePort = 30000
+	maxNodePort = 32767
+)
+
+// Annotations that may be set on a workload to configure the
+// KubernetesApplication produced by KubeAppWrapper.
+const (
+	// AnnotationKeyClusterSelector is a JSON encoded label selector that
+	// selects the KubernetesTargets to which the KubernetesApplication may be
+	// scheduled.
+	AnnotationKeyClusterSelector = "core.oam.dev/cluster-selector"
+
+	// AnnotationKeyClusterTarget is the <namespace>/<name> of the
+	// KubernetesTarget to which the KubernetesApplication should be
+	// scheduled. The KubernetesTarget's connection secret supplies the
+	// credentials used to reach its cluster. KubernetesApplications may only
+	// reference targets in their own namespace, so the namespace must be that
+	// of the workload.
+	AnnotationKeyClusterTarget = "core.oam.dev/cluster-target"
+)
+
+// Workload annotations with these prefixes are propagated to the Service
+// injected by ServiceInjector.
+var serviceAnnotationPrefixes = []string{
+	"service.beta.kubernetes.io/",
+	"service.oam.dev/",
+}
+
+// A WrapperOption configures a TranslationWrapper.
+type WrapperOption func(*wrapperOptions)
+
+type wrapperOptions struct {
+	labelKey                   string
+	strict                     bool
+	patches                    []jsonpatch.Operation
+	portNamer                  PortNamer
+	templateAnnotationPrefixes []string
+	supportsLoadBalancer       bool
+	mapper                     meta.RESTMapper
+	namePrefix                 string
+	nameSuffix                 string
+	appProtocols               map[string]string
+}
+
+// WithLabelKey configures the label used to associate translated objects with
+// their workload. LabelKey is used by default. Controllers that share a
+// cluster should use distinct label keys so that their selectors do not
+// collide.
+func WithLabelKey(k string) WrapperOption {
+	return func(o *wrapperOptions) {
+		o.labelKey = k
+	}
+}
+
+// WithStrict causes a TranslationWrapper to return ErrNoTranslatableObject
+// when it is supplied no object it knows how to handle, rather than returning
+// the supplied objects unchanged.
+func WithStrict() WrapperOption {
+	return func(o *wrapperOptions) {
+		o.strict = true
+	}
+}
+
+// A PortNamer returns the name of the Service port that exposes the supplied
+// port of the named container. The index is that of the port among all ports
+// exposed by the Service.
+type PortNamer func(container string, p corev1.ContainerPort, index int) string
+
+// WithPortNamer configures the PortNamer used to name the ports of injected
+// Services. Names produced by a PortNamer must be unique, valid IANA service
+// names; i.e. no more than 15 lowercase alphanumeric characters or '-'. By
+// default a port is named after its container port, or port-<number> if it
+// is unnamed. It is only honoured by ServiceInjector.
+func WithPortNamer(fn PortNamer) WrapperOption {
+	return func(o *wrapperOptions) {
+		o.portNamer = fn
+	}
+}
+
+// WithAppProtocols configures the mapping of container port names to the
+// application protocol of the Service ports that expose them. AppProtocols is
+// used by default. It is only honoured by ServiceInjector.
+func WithAppProtocols(p map[string]string) WrapperOption {
+	return func(o *wrapperOptions) {
+		o.appProtocols = p
+	}
+}
+
+// maxPortNameLength is the maximum length of an IANA service name, and thus of
+// a Service port name.
+const maxPortNameLength = 15
+
+// ContainerPortNamer is a PortNamer that names each port after its container
+// and container port, i.e. <container>-<port-name>. Unnamed ports are named
+// <container>-<number>, suffixed with their lowercase protocol unless it is
+// TCP. Names longer than an IANA service name are truncated, and suffixed
+// with a hash of the original name to keep them unique.
+func ContainerPortNamer(container string, p corev1.ContainerPort, _ int) string {
+	port := p.Name
+	if port == "" {
+		port = strconv.Itoa(int(p.ContainerPort))
+		if p.Protocol != "" && p.Protocol != corev1.ProtocolTCP {
+			port += "-" + strings.ToLower(string(p.Protocol))
+		}
+	}
+	return truncatePortName(container + "-" + port)
+}
+
+// truncatePortName truncates port names longer than an IANA service name, and
+// suffixes them with a hash of the original name to keep them unique.
+func truncatePortName(name string) string {
+	if len(name) <= maxPortNameLength {
+		return name
+	}
+
+	// IANA service names may not contain consecutive hyphens, so we can't
+	// use truncate, which may leave a hyphen before the hash.
+	h := sha256.Sum256([]byte(name))
+	suffix := hex.EncodeToString(h[:])[:4]
+	return strings.TrimRight(name[:maxPortNameLength-len(suffix)-1], "-") + "-" + suffix
+}
+
+// WithLoadBalancerSupport configures whether the cluster that translated
+// objects are deployed to supports LoadBalancer Services. Clusters without a
+// load balancer implementation, such as bare-metal or kind clusters, never
+// provision LoadBalancer Services, leaving them pending forever. When support
+// is disabled a NodePort Service is injected in place of any LoadBalancer
+// Service, and a Warning is recorded. Support is assumed by default. It is
+// only honoured by ServiceInjector.
+func WithLoadBalancerSupport(supported bool) WrapperOption {
+	return func(o *wrapperOptions) {
+		o.supportsLoadBalancer = supported
+	}
+}
+
+// WithRESTMapper configures the RESTMapper used to determine whether a wrapped
+// object is cluster scoped, and therefore must not be placed in a namespace.
+// Kinds the RESTMapper does not know, or all kinds if no RESTMapper is
+// configured, are looked up in a set of well known cluster scoped kinds such
+// as ClusterRole and PriorityClass. It is only honoured by KubeAppWrapper.
+func WithRESTMapper(m meta.RESTMapper) WrapperOption {
+	return func(o *wrapperOptions) {
+		o.mapper = m
+	}
+}
+
+// WithNamePrefix configures KubeAppWrapper to prefix the name of the
+// KubernetesApplication it produces with the supplied string, for example to
+// avoid colliding with another object named after the same workload. The
+// resulting name must be a valid DNS subdomain.
+func WithNamePrefix(p string) WrapperOption {
+	return func(o *wrapperOptions) {
+		o.namePrefix = p
+	}
+}
+
+// WithNameSuffix configures KubeAppWrapper to suffix the name of the
+// KubernetesApplication it produces with the supplied string. The resulting
+// name must be a valid DNS subdomain.
+func WithNameSuffix(s string) WrapperOption {
+	return func(o *wrapperOptions) {
+		o.nameSuffix = s
+	}
+}
+
+// WithTemplateAnnotations configures KubeAppWrapper to annotate each resource
+// template with the workload's annotations that have any of the supplied
+// prefixes, for example to control the order in which a downstream controller
+// syncs templates. Annotations with the same prefixes on the wrapped object
+// are merged in, and take precedence over those of the workload. Prefixes
+// accumulate if this option is supplied more than once.
+func WithTemplateAnnotations(prefixes ...string) WrapperOption {
+	return func(o *wrapperOptions) {
+		o.templateAnnotationPrefixes = append(o.templateAnnotationPrefixes, prefixes...)
+	}
+}
+
+// WithPatches configures RFC 6902 JSON patch operations that are applied to
+// the JSON representation of each wrapped object before it is embedded in a
+// resource template. Patches are applied in the order they are supplied. It
+// is only honoured by KubeAppWrapper.
+func WithPatches(ops ...jsonpatch.Operation) WrapperOption {
+	return func(o *wrapperOptions) {
+		o.patches = append(o.patches, ops...)
+	}
+}
+
+// newWrapperOptions returns the default wrapper options, modified by the
+// supplied WrapperOptions.
+func newWrapperOptions(o ...WrapperOption) wrapperOptions {
+	opts := wrapperOptions{labelKey: LabelKey, supportsLoadBalancer: true, appProtocols: AppProtocols}
+	for _, fn := range o {
+		fn(&opts)
+	}
+	return opts
+}
+
+// NoopWrapper returns the supplied objects unchanged. It is useful as a
+// placeholder for a translation stage that has been disabled.
+func NoopWrapper(ctx context.Context, w resource.Workload, objs []resource.Object) ([]resource.Object, error) {
+	return objs, nil
+}
+
 // KubeAppWrapper wraps a set of translated objects in a KubernetesApplication.
+// Each wrapped object that is not already in a namespace is placed in the
+// namespace of the workload, unless it is of a well known cluster scoped kind
+// such as ClusterRole or PriorityClass. Use NewKubeAppWrapper with
+// WithRESTMapper to teach the wrapper about other cluster scoped kinds, such
+// as those defined by CustomResourceDefinitions.
+//
+// Resource templates are named <object-name>-<lowercase-object-kind>, so any
+// kind of object may be wrapped without the names of their templates
+// colliding. Objects whose names already end in -<lowercase-object-kind>, such
+// as a Deployment named <workload-name>-deployment, are not suffixed again.
+// Object names that would produce a template name longer than the
+// 253 character DNS subdomain limit are truncated, and suffixed with a hash of
+// the original name to keep them unique. Resource templates are ordered by the
+// kind and then name of the object they wrap, regardless of the order in which
+// objects are supplied. Resource templates inherit the labels of the object
+// they wrap, and are additionally labelled with the workload's UID using
+// LabelKey, or the label key configured by WithLabelKey.
+//
+// The KubernetesApplication's target selector may be set using the
+// AnnotationKeyClusterSelector workload annotation, and its target using the
+// AnnotationKeyClusterTarget workload annotation. Objects that include a
+// KubernetesApplication are assumed to have already been wrapped, and are
+// returned unchanged.
 func KubeAppWrapper(ctx context.Context, w resource.Workload, objs []resource.Object) ([]resource.Object, error) {
-	if objs == nil {
-		return nil, nil
-	}
+	return NewKubeAppWrapper()(ctx, w, objs)
+}
 
-	app := &workloadv1alpha1.KubernetesApplication{}
+// NewKubeAppWrapper returns a TranslationWrapper that behaves like
+// KubeAppWrapper, configured by the supplied options. When configured using
+// WithStrict it returns ErrNoTranslatableObject if no objects were supplied.
+// When configured using WithPatches each object is patched before it is
+// embedded in its resource template.
+// When configured using WithTemplateAnnotations resource templates are
+// annotated with the workload's annotations. When configured using
+// WithRESTMapper the RESTMapper determines which objects are cluster scoped.
+// The KubernetesApplication may be named using WithNamePrefix and
+// WithNameSuffix.
+func NewKubeAppWrapper(options ...WrapperOption) workload.TranslationWrapper {
+	opts := newWrapperOptions(options...)
+	return func(ctx context.Context, w resource.Workload, objs []resource.Object) ([]resource.Object, error) {
+		if len(objs) == 0 {
+			if opts.strict {
+				return nil, ErrNoTranslatableObject
+			}
+			if objs == nil {
+				return nil, nil
+			}
+		}
 
-	for _, o := range objs {
-		b, err := json.Marshal(o)
-		if err != nil {
-			return nil, errors.Wrap(err, errWrapInKubeApp)
+		if wrapped(objs) {
+			return objs, nil
+		}
+
+		app := &workloadv1alpha1.KubernetesApplication{}
+
+		if v, ok := w.GetAnnotations()[AnnotationKeyClusterSelector]; ok {
+			sel := &metav1.LabelSelector{}
+			if err := json.Unmarshal([]byte(v), sel); err != nil {
+				return nil, errors.Wrap(err, errParseClusterSelector)
+			}
+			app.Spec.TargetSelector = sel
+		}
+
+		if v, ok := w.GetAnnotations()[AnnotationKeyClusterTarget]; ok {
+			ref, err := clusterTarget(w, v)
+			if err != nil {
+				return nil, err
+			}
+			app.Spec.Target = ref
 		}
 
-		kart := workloadv1alpha1.KubernetesApplicationResourceTemplate{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: fmt.Sprintf("%s-%s", o.GetName(), strings.ToLower(o.GetObjectKind().GroupVersionKind().Kind)),
-				Labels: map[string]string{
-					LabelKey: string(w.GetUID()),
+		for _, o := range sortedByKindAndName(objs) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			cs, err := clusterScoped(opts.mapper, o)
+			if err != nil {
+				return nil, errors.Wrap(err, errWrapInKubeApp)
+			}
+			if o.GetNamespace() == "" && !cs {
+				o.SetNamespace(w.GetNamespace())
+			}
+
+			b, err := json.Marshal(o)
+			if err != nil {
+				return nil, errors.Wrap(errors.Wrapf(err, errFmtMarshalObject, o.GetObjectKind().GroupVersionKind().Kind, o.GetName()), errWrapInKubeApp)
+			}
+
+			if len(opts.patches) > 0 {
+				if b, err = jsonpatch.Patch(opts.patches).Apply(b); err != nil {
+					return nil, errors.Wrap(errors.Wrapf(err, errFmtPatchObject, o.GetObjectKind().GroupVersionKind().Kind, o.GetName()), errWrapInKubeApp)
+				}
+			}
+
+			labels := map[string]string{}
+			for k, v := range o.GetLabels() {
+				labels[k] = v
+			}
+			labels[opts.labelKey] = string(w.GetUID())
+
+			var annotations map[string]string
+			if len(opts.templateAnnotationPrefixes) > 0 {
+				annotations = prefixedAnnotations(w, opts.templateAnnotationPrefixes)
+				for k, v := range prefixedAnnotations(o, opts.templateAnnotationPrefixes) {
+					if annotations == nil {
+						annotations = map[string]string{}
+					}
+					annotations[k] = v
+				}
+			}
+
+			name, err := templateName(o.GetName(), o.GetObjectKind().GroupVersionKind().Kind)
+			if err != nil {
+				return nil, errors.Wrap(err, errWrapInKubeApp)
+			}
+
+			kart := workloadv1alpha1.KubernetesApplicationResourceTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        name,
+					Labels:      labels,
+					Annotations: annotations,
 				},
-			},
-			Spec: workloadv1alpha1.KubernetesApplicationResourceSpec{
-				Template: runtime.RawExtension{Raw: b},
+				Spec: workloadv1alpha1.KubernetesApplicationResourceSpec{
+					Template: runtime.RawExtension{Raw: b},
+				},
+			}
+
+			app.Spec.ResourceTemplates = append(app.Spec.ResourceTemplates, kart)
+		}
+
+		name := opts.namePrefix + w.GetName() + opts.nameSuffix
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			return nil, errors.Wrap(errors.Errorf(errFmtInvalidAppName, name, strings.Join(errs, ", ")), errWrapInKubeApp)
+		}
+		app.SetName(name)
+
+		app.Spec.ResourceSelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				opts.labelKey: string(w.GetUID()),
 			},
 		}
 
-		app.Spec.ResourceTemplates = append(app.Spec.ResourceTemplates, kart)
+		return []resource.Object{app}, nil
 	}
+}
 
-	app.SetName(w.GetName())
+// wrapped returns true if the supplied objects include a
+// KubernetesApplication.
+func wrapped(objs []resource.Object) bool {
+	for _, o := range objs {
+		if _, ok := o.(*workloadv1alpha1.KubernetesApplication); ok {
+			return true
+		}
+	}
+	return false
+}
 
-	app.Spec.ResourceSelector = &metav1.LabelSelector{
-		MatchLabels: map[string]string{
-			LabelKey: string(w.GetUID()),
-		},
+// clusterTarget returns a reference to the KubernetesTarget identified by the
+// supplied <namespace>/<name>, which must be in the supplied workload's
+// namespace.
+func clusterTarget(w resource.Workload, v string) (*workloadv1alpha1.KubernetesTargetReference, error) {
+	parts := strings.Split(v, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, errors.Errorf(errFmtInvalidTarget, v)
+	}
+	if parts[0] != w.GetNamespace() {
+		return nil, errors.Errorf(errFmtTargetNamespace, v, w.GetNamespace())
 	}
+	return &workloadv1alpha1.KubernetesTargetReference{Name: parts[1]}, nil
+}
 
-	return []resource.Object{app}, nil
+// templateName returns the name of the KubernetesApplicationResourceTemplate
+// that wraps an object of the supplied name and kind. Names that already end
+// in the kind are not suffixed again. An error is returned if the name is not
+// a valid DNS subdomain.
+func templateName(name, kind string) (string, error) {
+	suffix := "-" + strings.ToLower(kind)
+	name = strings.TrimSuffix(name, suffix)
+	n := truncate(name, validation.DNS1123SubdomainMaxLength-len(suffix)) + suffix
+	if errs := validation.IsDNS1123Subdomain(n); len(errs) > 0 {
+		return "", errors.Errorf(errFmtInvalidTemplate, n, strings.Join(errs, ", "))
+	}
+	return n, nil
+}
+
+// sortedByKindAndName returns a copy of the supplied objects, sorted by kind
+// and then by name.
+func sortedByKindAndName(objs []resource.Object) []resource.Object {
+	sorted := make([]resource.Object, len(objs))
+	copy(sorted, objs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ki := sorted[i].GetObjectKind().GroupVersionKind().Kind
+		kj := sorted[j].GetObjectKind().GroupVersionKind().Kind
+		if ki != kj {
+			return ki < kj
+		}
+		return sorted[i].GetName() < sorted[j].GetName()
+	})
+	return sorted
 }
 
-// ServiceInjector adds a Service object for the first Port on the first
-// Container for the first Deployment observed in a workload translation.
+// ServiceInjector adds a Service object for the first Deployment, StatefulSet,
+// or DaemonSet observed in a workload translation. The Service exposes every
+// port declared by every Container of its pod template, deduplicated by port
+// number and protocol. The supplied objects are returned in their original
+// order, followed by any injected Services.
+//
+// The Service is named <workload-name>-svc, regardless of the name of the
+// object whose pods it exposes. Workload names that would produce a Service
+// name longer than the 63 character DNS label limit are truncated, and
+// suffixed with a hash of the original name to keep them unique. The type of the
+// Service may be set using the AnnotationKeyServiceType workload annotation,
+// its node ports using the AnnotationKeyNodePort annotation, and its session
+// affinity using the AnnotationKeySessionAffinity annotation. The IP address
+// and source ranges of a LoadBalancer Service may be set using the
+// AnnotationKeyLoadBalancerIP and AnnotationKeyLoadBalancerSourceRanges
+// annotations, and the external traffic policy of a NodePort or LoadBalancer
+// Service using the AnnotationKeyExternalTrafficPolicy annotation.
+// No Service is injected if the workload opts out using the
+// AnnotationKeyNoService annotation, or if the translation already includes a
+// Service that selects the workload's pods.
+//
+// When the AnnotationKeyServiceContainer annotation is set, the Service
+// exposes only the ports of the named container, for example to expose an
+// application but not its sidecar. When the AnnotationKeyServicePerContainer
+// annotation is set, a Service is instead injected for each container of the
+// pod template that declares ports. Each Service is named
+// <workload-name>-<container-name> and exposes only the ports of its
+// container, but selects the same pods.
+//
+// When the AnnotationKeyPrefixPortNames annotation is set the Service's ports
+// are named <container>-<port-name>, keeping the names of ports aggregated
+// from several containers distinct.
+//
+// Ports whose container port has a well-known name such as grpc or http2 are
+// named after their application protocol, i.e. <app-protocol>-<name>, unless
+// they already are, so that service meshes route them correctly. The mapping
+// of names to application protocols is AppProtocols, and the application
+// protocol of a port may be overridden using the AnnotationKeyAppProtocol
+// annotation. Other ports keep their names, as do ports named using a
+// PortNamer or the AnnotationKeyPrefixPortNames annotation.
+//
+// The Service of a pod template that uses the host's network targets the host
+// ports of its containers by number, rather than their container ports.
+//
+// Workload annotations prefixed with service.beta.kubernetes.io/ or
+// service.oam.dev/ are copied verbatim to the injected Service, allowing
+// providers' load balancer behaviour to be configured. All other workload
+// annotations, including those that configure ServiceInjector itself, are
+// ignored.
 func ServiceInjector(ctx context.Context, w resource.Workload, objs []resource.Object) ([]resource.Object, error) {
-	if objs == nil {
-		return nil, nil
+	return NewServiceInjector()(ctx, w, objs)
+}
+
+// NewServiceInjector returns a TranslationWrapper that behaves like
+// ServiceInjector, configured by the supplied options. When configured using
+// WithStrict it returns ErrNoTranslatableObject if no Service was injected
+// because no object with a pod template was supplied.
+// Service ports may be named using WithPortNamer, and LoadBalancer Services
+// downgraded to NodePort Services using WithLoadBalancerSupport.
+func NewServiceInjector(options ...WrapperOption) workload.TranslationWrapper {
+	opts := newWrapperOptions(options...)
+	return func(ctx context.Context, w resource.Workload, objs []resource.Object) ([]resource.Object, error) {
+		if objs == nil && !opts.strict {
+			return nil, nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if w.GetAnnotations()[AnnotationKeyNoService] == "true" || hasService(opts.labelKey, w, objs) {
+			return objs, nil
+		}
+
+		spec, err := serviceSpec(w)
+		if err != nil {
+			return nil, err
+		}
+		downgraded := false
+		if spec.Type == corev1.ServiceTypeLoadBalancer && !opts.supportsLoadBalancer {
+			downgradeLoadBalancer(&spec)
+			downgraded = true
+		}
+
+		np, err := nodePorts(w, spec.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		ap, err := appProtocols(w)
+		if err != nil {
+			return nil, err
+		}
+
+		// Services are appended to a copy of the supplied objects, so that
+		// the caller's slice is never modified even if it has spare capacity.
+		out := make([]resource.Object, len(objs), len(objs)+1)
+		copy(out, objs)
+
+		found := false
+		for _, o := range objs {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			// Jobs run to completion, so their pods are not exposed.
+			if _, ok := o.(*batchv1.Job); ok {
+				continue
+			}
+			t := PodTemplate(o)
+			if t == nil {
+				continue
+			}
+
+			// We don't add a Service if there are no containers for the workload.
+			// This should never happen in practice.
+			if len(t.Spec.Containers) < 1 {
+				continue
+			}
+			found = true
+
+			if name, ok := w.GetAnnotations()[AnnotationKeyServiceContainer]; ok {
+				c, err := serviceContainer(name, t.Spec.Containers)
+				if err != nil {
+					return nil, err
+				}
+				sn, err := serviceName(w.GetName())
+				if err != nil {
+					return nil, err
+				}
+				svc, err := newService(ctx, opts, w, sn, spec, np, ap, *t, []corev1.Container{c})
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, svc)
+				break
+			}
+
+			if w.GetAnnotations()[AnnotationKeyServicePerContainer] == "true" {
+				for _, c := range t.Spec.Containers {
+					if len(c.Ports) == 0 {
+						continue
+					}
+					name, err := containerServiceName(w.GetName(), c.Name)
+					if err != nil {
+						return nil, err
+					}
+					svc, err := newService(ctx, opts, w, name, spec, np, ap, *t, []corev1.Container{c})
+					if err != nil {
+						return nil, err
+					}
+					out = append(out, svc)
+				}
+				break
+			}
+
+			name, err := serviceName(w.GetName())
+			if err != nil {
+				return nil, err
+			}
+
+			// We only add a single Service for the workload, even if multiple
+			// containers or no ports are defined. This is to exclude the need for
+			// implementing garbage collection in the short-term in the case that
+			// ports are modified after creation.
+			svc, err := newService(ctx, opts, w, name, spec, np, ap, *t, t.Spec.Containers)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, svc)
+			break
+		}
+		if !found && opts.strict {
+			return nil, ErrNoTranslatableObject
+		}
+		if downgraded && len(out) > len(objs) {
+			RecordWarning(ctx, Warning{
+				Field:  fmt.Sprintf("metadata.annotations[%s]", AnnotationKeyServiceType),
+				Reason: reasonLoadBalancerDowngraded,
+			})
+		}
+		return out, nil
+	}
+}
+
+// newService returns a Service with the supplied name and spec that selects the
+// pods of the supplied pod template, and exposes the ports of the supplied
+// containers using the supplied node ports and application protocols, keyed by
+// port number.
+func newService(ctx context.Context, opts wrapperOptions, w resource.Workload, name string, spec corev1.ServiceSpec, np map[int32]int32, ap map[int32]string, t corev1.PodTemplateSpec, cs []corev1.Container) (*corev1.Service, error) {
+	pn := opts.portNamer
+	if w.GetAnnotations()[AnnotationKeyPrefixPortNames] == "true" {
+		pn = ContainerPortNamer
+	}
+	ports, err := servicePorts(ctx, cs, pn)
+	if err != nil {
+		return nil, err
+	}
+	// Ports named by a PortNamer keep the names they were given.
+	if pn == nil {
+		if err := nameAppProtocols(ports, opts.appProtocols, ap); err != nil {
+			return nil, err
+		}
+	}
+	if t.Spec.HostNetwork {
+		targetHostPorts(ports, cs)
+	}
+	spec.Selector = serviceSelector(opts.labelKey, w, t)
+	spec.Ports = ports
+	for i := range spec.Ports {
+		spec.Ports[i].NodePort = np[spec.Ports[i].Port]
+	}
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       serviceKind,
+			APIVersion: serviceAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				opts.labelKey: string(w.GetUID()),
+			},
+			Annotations: serviceAnnotations(w),
+		},
+		Spec: spec,
+	}, nil
+}
+
+// targetHostPorts updates the supplied Service ports to target the host ports
+// of the supplied containers by number. Pods that use the host's network
+// serve on their host ports, which default to the container port of the same
+// number.
+func targetHostPorts(ports []corev1.ServicePort, cs []corev1.Container) {
+	hp := map[portKey]int32{}
+	for _, c := range cs {
+		for _, p := range c.Ports {
+			if p.Protocol == "" {
+				p.Protocol = corev1.ProtocolTCP
+			}
+			k := portKey{port: p.ContainerPort, protocol: p.Protocol}
+			if _, ok := hp[k]; ok {
+				continue
+			}
+			hp[k] = p.ContainerPort
+			if p.HostPort != 0 {
+				hp[k] = p.HostPort
+			}
+		}
+	}
+	for i := range ports {
+		if n, ok := hp[portKey{port: ports[i].Port, protocol: ports[i].Protocol}]; ok {
+			ports[i].TargetPort = intstr.FromInt(int(n))
+		}
+	}
+}
+
+// serviceContainer returns the container with the supplied name, or an error
+// if there is no such container or it declares no ports.
+func serviceContainer(name string, cs []corev1.Container) (corev1.Container, error) {
+	for _, c := range cs {
+		if c.Name != name {
+			continue
+		}
+		if len(c.Ports) == 0 {
+			return corev1.Container{}, errors.Errorf(errFmtNoContainerPorts, name)
+		}
+		return c, nil
 	}
+	return corev1.Container{}, errors.Errorf(errFmtNoServiceContainer, name)
+}
 
+// PodTemplate returns the pod template of the supplied object, or nil if it is
+// not a non-nil Deployment, StatefulSet, DaemonSet, or Job. Changes to the
+// returned template are made to the supplied object.
+func PodTemplate(o resource.Object) *corev1.PodTemplateSpec {
+	switch w := o.(type) {
+	case *appsv1.Deployment:
+		if w != nil {
+			return &w.Spec.Template
+		}
+	case *appsv1.StatefulSet:
+		if w != nil {
+			return &w.Spec.Template
+		}
+	case *appsv1.DaemonSet:
+		if w != nil {
+			return &w.Spec.Template
+		}
+	case *batchv1.Job:
+		if w != nil {
+			return &w.Spec.Template
+		}
+	}
+	return nil
+}
+
+// hasService returns true if the supplied objects include a Service that
+// selects the pods of the supplied workload.
+func hasService(labelKey string, w resource.Workload, objs []resource.Object) bool {
 	for _, o := range objs {
-		d, ok := o.(*appsv1.Deployment)
-		if !ok {
+		s, ok := o.(*corev1.Service)
+		if !ok || s == nil {
 			continue
 		}
+		if v, ok := s.Spec.Selector[labelKey]; ok && v == string(w.GetUID()) {
+			return true
+		}
+	}
+	return false
+}
+
+// serviceName returns the name of the Service injected for an object with the
+// supplied name. The name is truncated if necessary to produce a valid DNS-1035
+// label.
+func serviceName(name string) (string, error) {
+	n := truncate(name, validation.DNS1035LabelMaxLength-len(ServiceNameSuffix)) + ServiceNameSuffix
+	if errs := validation.IsDNS1035Label(n); len(errs) > 0 {
+		return "", errors.Errorf(errFmtInvalidServiceName, n, strings.Join(errs, ", "))
+	}
+	return n, nil
+}
+
+// containerServiceName returns the name of the Service injected for the
+// supplied container of the supplied workload, i.e. <workload>-<container>.
+// Names longer than the 63 character DNS label limit are truncated, and
+// suffixed with a hash of the original name.
+func containerServiceName(workload, container string) (string, error) {
+	n := truncate(workload+"-"+container, validation.DNS1035LabelMaxLength)
+	if errs := validation.IsDNS1035Label(n); len(errs) > 0 {
+		return "", errors.Errorf(errFmtInvalidServiceName, n, strings.Join(errs, ", "))
+	}
+	return n, nil
+}
+
+// truncate returns the supplied name unchanged if it is no longer than max
+// characters. Longer names are shortened to max characters, replacing their
+// tail with a hash of the original name so that distinct names remain
+// distinct.
+func truncate(name string, max int) string {
+	if len(name) <= max {
+		return name
+	}
+	h := sha256.Sum256([]byte(name))
+	suffix := hex.EncodeToString(h[:])[:8]
+	return name[:max-len(suffix)-1] + "-" + suffix
+}
+
+// serviceAnnotations returns the annotations of the supplied workload that
+// should be propagated to its Service, or nil if there are none.
+func serviceAnnotations(w resource.Workload) map[string]string {
+	return prefixedAnnotations(w, serviceAnnotationPrefixes)
+}
+
+// serviceSelector returns a Service selector that matches the labels of the
+// supplied pod template. The selector always includes the supplied label key,
+// set to the UID of the supplied workload, which takes precedence over any pod
+// template label.
+func serviceSelector(labelKey string, w resource.Workload, t corev1.PodTemplateSpec) map[string]string {
+	sel := map[string]string{}
+	for k, v := range t.GetLabels() {
+		sel[k] = v
+	}
+	sel[labelKey] = string(w.GetUID())
+	return sel
+}
+
+// serviceSpec returns the ServiceSpec configured by the annotations of the
+// supplied workload. The caller is responsible for setting its selector and
+// ports.
+func serviceSpec(w resource.Workload) (corev1.ServiceSpec, error) {
+	spec, err := serviceTypeSpec(w)
+	if err != nil {
+		return corev1.ServiceSpec{}, err
+	}
+
+	a, cfg, err := sessionAffinity(w)
+	if err != nil {
+		return corev1.ServiceSpec{}, err
+	}
+	spec.SessionAffinity = a
+	spec.SessionAffinityConfig = cfg
+
+	if err := loadBalancer(w, &spec); err != nil {
+		return corev1.ServiceSpec{}, err
+	}
+
+	p, err := externalTrafficPolicy(w, spec.Type)
+	if err != nil {
+		return corev1.ServiceSpec{}, err
+	}
+	spec.ExternalTrafficPolicy = p
+
+	return spec, nil
+}
+
+// externalTrafficPolicy returns the external traffic policy requested by the
+// annotations of the supplied workload for a Service of the supplied type.
+func externalTrafficPolicy(w resource.Workload, t corev1.ServiceType) (corev1.ServiceExternalTrafficPolicyType, error) {
+	v, ok := w.GetAnnotations()[AnnotationKeyExternalTrafficPolicy]
+	if !ok {
+		return "", nil
+	}
+	if t != corev1.ServiceTypeNodePort && t != corev1.ServiceTypeLoadBalancer {
+		return "", errors.Errorf(errFmtTrafficPolicyType, t)
+	}
+	switch p := corev1.ServiceExternalTrafficPolicyType(v); p {
+	case corev1.ServiceExternalTrafficPolicyTypeLocal, corev1.ServiceExternalTrafficPolicyTypeCluster:
+		return p, nil
+	default:
+		return "", errors.Errorf(errFmtInvalidTraffic, v)
+	}
+}
+
+// loadBalancer configures the supplied LoadBalancer ServiceSpec using the
+// annotations of the supplied workload.
+func loadBalancer(w resource.Workload, spec *corev1.ServiceSpec) error {
+	a := w.GetAnnotations()
+	ip, hasIP := a[AnnotationKeyLoadBalancerIP]
+	ranges, hasRanges := a[AnnotationKeyLoadBalancerSourceRanges]
+	if !hasIP && !hasRanges {
+		return nil
+	}
+	if spec.Type != corev1.ServiceTypeLoadBalancer {
+		return errors.Errorf(errFmtLoadBalancerType, spec.Type)
+	}
+
+	if hasIP {
+		if net.ParseIP(ip) == nil {
+			return errors.Errorf(errFmtInvalidLBIP, ip)
+		}
+		spec.LoadBalancerIP = ip
+	}
+
+	if hasRanges {
+		for _, r := range strings.Split(ranges, ",") {
+			r = strings.TrimSpace(r)
+			if _, _, err := net.ParseCIDR(r); err != nil {
+				return errors.Errorf(errFmtInvalidSourceRange, r)
+			}
+			spec.LoadBalancerSourceRanges = append(spec.LoadBalancerSourceRanges, r)
+		}
+	}
+
+	return nil
+}
+
+// downgradeLoadBalancer converts the supplied LoadBalancer ServiceSpec to a
+// NodePort ServiceSpec, dropping settings that only apply to load balancers.
+// Node ports and the external traffic policy apply to both types, and are
+// preserved.
+func downgradeLoadBalancer(spec *corev1.ServiceSpec) {
+	spec.Type = corev1.ServiceTypeNodePort
+	spec.LoadBalancerIP = ""
+	spec.LoadBalancerSourceRanges = nil
+}
+
+// serviceTypeSpec returns a ServiceSpec with the type and cluster IP
+// configured by the annotations of the supplied workload.
+func serviceTypeSpec(w resource.Workload) (corev1.ServiceSpec, error) {
+	a := w.GetAnnotations()
+	headless := a[AnnotationKeyHeadless] == "true"
+
+	v, ok := a[AnnotationKeyServiceType]
+	if !ok {
+		if headless {
+			return corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, ClusterIP: corev1.ClusterIPNone}, nil
+		}
+		return corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer}, nil
+	}
+
+	spec := corev1.ServiceSpec{}
+	switch t := corev1.ServiceType(v); t {
+	case corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer:
+		spec.Type = t
+	default:
+		return corev1.ServiceSpec{}, errors.Errorf(errFmtInvalidServiceType, v)
+	}
+
+	if headless {
+		if spec.Type != corev1.ServiceTypeClusterIP {
+			return corev1.ServiceSpec{}, errors.New(errHeadlessServiceType)
+		}
+		spec.ClusterIP = corev1.ClusterIPNone
+	}
+
+	return spec, nil
+}
+
+// sessionAffinity returns the session affinity, and its configuration,
+// requested by the annotations of the supplied workload.
+func sessionAffinity(w resource.Workload) (corev1.ServiceAffinity, *corev1.SessionAffinityConfig, error) {
+	a := w.GetAnnotations()
+
+	var aff corev1.ServiceAffinity
+	if v, ok := a[AnnotationKeySessionAffinity]; ok {
+		switch aff = corev1.ServiceAffinity(v); aff {
+		case corev1.ServiceAffinityClientIP, corev1.ServiceAffinityNone:
+		default:
+			return "", nil, errors.Errorf(errFmtInvalidSessionAffinity, v)
+		}
+	}
+
+	v, ok := a[AnnotationKeySessionAffinityTimeout]
+	if !ok {
+		return aff, nil, nil
+	}
+	if aff != corev1.ServiceAffinityClientIP {
+		return "", nil, errors.New(errTimeoutWithoutClientIP)
+	}
+	t, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		return "", nil, errors.Wrap(err, errParseSessionAffinityTimeout)
+	}
+	if t < 1 || t > maxSessionAffinityTimeout {
+		return "", nil, errors.Errorf(errFmtSessionAffinityTimeoutSecs, t, maxSessionAffinityTimeout)
+	}
+	timeout := int32(t)
+	return aff, &corev1.SessionAffinityConfig{ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: &timeout}}, nil
+}
+
+// nodePorts returns the node ports requested by the AnnotationKeyNodePort
+// annotation of the supplied workload, keyed by port number.
+func nodePorts(w resource.Workload, t corev1.ServiceType) (map[int32]int32, error) {
+	v, ok := w.GetAnnotations()[AnnotationKeyNodePort]
+	if !ok {
+		return nil, nil
+	}
+	if t != corev1.ServiceTypeNodePort && t != corev1.ServiceTypeLoadBalancer {
+		return nil, errors.Errorf(errFmtNodePortType, t)
+	}
 
-		// We don't add a Service if there are no containers for the Deployment.
-		// This should never happen in practice.
-		if len(d.Spec.Template.Spec.Containers) < 1 {
+	np := map[int32]int32{}
+	for _, pair := range strings.Split(v, ",") {
+		parts := strings.Split(strings.TrimSpace(pair), ":")
+		if len(parts) != 2 {
+			return nil, errors.Errorf(errFmtInvalidNodePort, pair)
+		}
+		port, err := strconv.ParseInt(parts[0], 10, 32)
+		if err != nil {
+			return nil, errors.Errorf(errFmtInvalidNodePort, pair)
+		}
+		nodePort, err := strconv.ParseInt(parts[1], 10, 32)
+		if err != nil {
+			return nil, errors.Errorf(errFmtInvalidNodePort, pair)
+		}
+		if nodePort < minNodePort || nodePort > maxNodePort {
+			return nil, errors.Errorf(errFmtNodePortRange, nodePort, port, minNodePort, maxNodePort)
+		}
+		np[int32(port)] = int32(nodePort)
+	}
+	return np, nil
+}
+
+// appProtocols returns the application protocols requested by the
+// AnnotationKeyAppProtocol annotation of the supplied workload, keyed by port
+// number.
+func appProtocols(w resource.Workload) (map[int32]string, error) {
+	v, ok := w.GetAnnotations()[AnnotationKeyAppProtocol]
+	if !ok {
+		return nil, nil
+	}
+
+	ap := map[int32]string{}
+	for _, pair := range strings.Split(v, ",") {
+		parts := strings.Split(strings.TrimSpace(pair), ":")
+		if len(parts) != 2 || parts[1] == "" {
+			return nil, errors.Errorf(errFmtInvalidAppProtocol, pair)
+		}
+		port, err := strconv.ParseInt(parts[0], 10, 32)
+		if err != nil {
+			return nil, errors.Errorf(errFmtInvalidAppProtocol, pair)
+		}
+		ap[int32(port)] = parts[1]
+	}
+	return ap, nil
+}
+
+// nameAppProtocols names the supplied Service ports after their application
+// protocol, i.e. <app-protocol>-<name>, unless they are already so named.
+// Service meshes such as Istio infer the protocol of a port from its name.
+// A port's application protocol is that set by the supplied overrides, keyed
+// by port number, or else that which the supplied mapping associates with the
+// name of its target container port. Names longer than an IANA service name
+// are truncated, and suffixed with a hash of the original name. Ports without
+// an application protocol keep their names. An error is returned if a port
+// would be given an invalid or duplicate name.
+//
+// TODO: Set ServicePort.AppProtocol too once we depend on k8s.io/api
+// v0.18 or later. The field does not exist in the version we depend on.
+func nameAppProtocols(ports []corev1.ServicePort, mapping map[string]string, overrides map[int32]string) error {
+	names := map[string]bool{}
+	for _, p := range ports {
+		names[p.Name] = true
+	}
+	for i := range ports {
+		p := &ports[i]
+		ap, ok := overrides[p.Port]
+		if !ok && p.TargetPort.Type == intstr.String {
+			ap, ok = mapping[p.TargetPort.StrVal]
+		}
+		if !ok || p.Name == ap || strings.HasPrefix(p.Name, ap+"-") {
 			continue
 		}
 
-		s := &corev1.Service{
-			TypeMeta: metav1.TypeMeta{
-				Kind:       serviceKind,
-				APIVersion: serviceAPIVersion,
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Name: d.GetName(),
-				Labels: map[string]string{
-					LabelKey: string(w.GetUID()),
-				},
-			},
-			Spec: corev1.ServiceSpec{
-				Selector: d.Spec.Selector.MatchLabels,
-				Ports:    []corev1.ServicePort{},
-				Type:     corev1.ServiceTypeLoadBalancer,
-			},
+		delete(names, p.Name)
+		name := truncatePortName(ap + "-" + p.Name)
+		if err := validatePortName(name, names); err != nil {
+			return err
 		}
+		names[name] = true
+		p.Name = name
+	}
+	return nil
+}
 
-		// We only add a single Service for the Deployment, even if multiple
-		// ports or no ports are defined on the first container. This is to
-		// exclude the need for implementing garbage collection in the
-		// short-term in the case that ports are modified after creation.
-		if len(d.Spec.Template.Spec.Containers[0].Ports) > 0 {
-			s.Spec.Ports = []corev1.ServicePort{
-				{
-					Name:       d.GetName(),
-					Port:       d.Spec.Template.Spec.Containers[0].Ports[0].ContainerPort,
-					TargetPort: intstr.FromInt(int(d.Spec.Template.Spec.Containers[0].Ports[0].ContainerPort)),
-				},
+// A portKey uniquely identifies a port exposed by a Service.
+type portKey struct {
+	port     int32
+	protocol corev1.Protocol
+}
+
+// servicePorts returns a ServicePort for every distinct port number and
+// protocol declared by the supplied containers. Ports keep their container port
+// name unless it is empty or already taken, in which case they are named
+// port-<number>. Names are suffixed with the lowercase protocol when the same
+// port number is exposed using more than one protocol. Named container ports
+// are targeted by name, and unnamed ports by number. A Warning is recorded for
+// each named port that is dropped because an earlier port with a different
+// name shares its number and protocol. When a PortNamer is supplied it names
+// every port instead, and an error is returned if it produces an invalid or
+// duplicate name.
+func servicePorts(ctx context.Context, cs []corev1.Container, pn PortNamer) ([]corev1.ServicePort, error) {
+	type containerPort struct {
+		container string
+		port      corev1.ContainerPort
+	}
+
+	cps := []containerPort{}
+	seenPorts := map[portKey]string{}
+	protocols := map[int32]int{}
+	for _, c := range cs {
+		for _, p := range c.Ports {
+			if p.Protocol == "" {
+				p.Protocol = corev1.ProtocolTCP
+			}
+			k := portKey{port: p.ContainerPort, protocol: p.Protocol}
+			if name, ok := seenPorts[k]; ok {
+				if p.Name != "" && p.Name != name {
+					RecordWarning(ctx, Warning{
+						Field:  fmt.Sprintf("containers[%s].ports[%s]", c.Name, p.Name),
+						Reason: fmt.Sprintf(reasonFmtDuplicatePort, p.ContainerPort, p.Protocol, name),
+					})
+				}
+				continue
 			}
+			seenPorts[k] = p.Name
+			protocols[p.ContainerPort]++
+			cps = append(cps, containerPort{container: c.Name, port: p})
 		}
-		objs = append(objs, s)
-		break
 	}
-	return objs, nil
+
+	ports := []corev1.ServicePort{}
+	seenNames := map[string]bool{}
+	for i, cp := range cps {
+		p := cp.port
+		var name string
+		switch {
+		case pn != nil:
+			name = pn(cp.container, p, i)
+			if err := validatePortName(name, seenNames); err != nil {
+				return nil, err
+			}
+		default:
+			name = p.Name
+			if name == "" || seenNames[name] {
+				name = fmt.Sprintf("port-%d", p.ContainerPort)
+			}
+			if protocols[p.ContainerPort] > 1 {
+				name = fmt.Sprintf("%s-%s", name, strings.ToLower(string(p.Protocol)))
+			}
+		}
+		seenNames[name] = true
+
+		// Named container ports are targeted by name, allowing the container
+		// port number to change without the Service being updated.
+		target := intstr.FromInt(int(p.ContainerPort))
+		if p.Name != "" {
+			target = intstr.FromString(p.Name)
+		}
+
+		ports = append(ports, corev1.ServicePort{
+			Name:       name,
+			Protocol:   p.Protocol,
+			Port:       p.ContainerPort,
+			TargetPort: target,
+		})
+	}
+	return ports, nil
+}
+
+// validatePortName returns an error if the supplied Service port name is not
+// a valid IANA service name, or has already been seen.
+func validatePortName(name string, seen map[string]bool) error {
+	if errs := validation.IsValidPortName(name); len(errs) > 0 {
+		return errors.Errorf(errFmtInvalidPortName, name, strings.Join(errs, ", "))
+	}
+	if seen[name] {
+		return errors.Errorf(errFmtDuplicatePortName, name)
+	}
+	return nil
 }
@@ -21,16 +21,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -49,6 +57,8 @@ var (
 	portName      = "test-port"
 )
 
+var errBoom = errors.New("boom")
+
 var (
 	deploymentKind       = reflect.TypeOf(appsv1.Deployment{}).Name()
 	deploymentAPIVersion = appsv1.SchemeGroupVersion.String()
@@ -61,7 +71,46 @@ func dmWithContainerPorts(ports ...int32) deploymentModifier {
 		p := []corev1.ContainerPort{}
 		for _, port := range ports {
 			p = append(p, corev1.ContainerPort{
-				Name:          portName,
+				Name:          fmt.Sprintf("%s-%d", portName, port),
+				ContainerPort: port,
+			})
+		}
+		d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{
+			Name:  containerName,
+			Ports: p,
+		})
+	}
+}
+
+func dmWithNamedContainerPorts(name string, ports ...int32) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		dmWithContainerPorts(ports...)(d)
+		d.Spec.Template.Spec.Containers[len(d.Spec.Template.Spec.Containers)-1].Name = name
+	}
+}
+
+func dmWithProtocolContainerPorts(protocol corev1.Protocol, ports ...int32) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		p := []corev1.ContainerPort{}
+		for _, port := range ports {
+			p = append(p, corev1.ContainerPort{
+				Name:          fmt.Sprintf("%s-%d", portName, port),
+				ContainerPort: port,
+				Protocol:      protocol,
+			})
+		}
+		d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{
+			Name:  containerName,
+			Ports: p,
+		})
+	}
+}
+
+func dmWithUnnamedContainerPorts(ports ...int32) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		p := []corev1.ContainerPort{}
+		for _, port := range ports {
+			p = append(p, corev1.ContainerPort{
 				ContainerPort: port,
 			})
 		}
@@ -72,6 +121,20 @@ func dmWithContainerPorts(ports ...int32) deploymentModifier {
 	}
 }
 
+func dmWithPodLabels(labels map[string]string) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		for k, v := range labels {
+			d.Spec.Template.Labels[k] = v
+		}
+	}
+}
+
+func dmWithNamespace(ns string) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.SetNamespace(ns)
+	}
+}
+
 func deployment(mod ...deploymentModifier) *appsv1.Deployment {
 	d := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
@@ -109,18 +172,172 @@ func deployment(mod ...deploymentModifier) *appsv1.Deployment {
 	return d
 }
 
+func statefulSet(mod ...deploymentModifier) *appsv1.StatefulSet {
+	d := deployment(mod...)
+	return &appsv1.StatefulSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       reflect.TypeOf(appsv1.StatefulSet{}).Name(),
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: d.ObjectMeta,
+		Spec: appsv1.StatefulSetSpec{
+			Selector: d.Spec.Selector,
+			Template: d.Spec.Template,
+		},
+	}
+}
+
+func daemonSet(mod ...deploymentModifier) *appsv1.DaemonSet {
+	d := deployment(mod...)
+	return &appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       reflect.TypeOf(appsv1.DaemonSet{}).Name(),
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: d.ObjectMeta,
+		Spec: appsv1.DaemonSetSpec{
+			Selector: d.Spec.Selector,
+			Template: d.Spec.Template,
+		},
+	}
+}
+
+func configMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       reflect.TypeOf(corev1.ConfigMap{}).Name(),
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+		},
+		Data: map[string]string{"cool": "config"},
+	}
+}
+
+func secret() *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       reflect.TypeOf(corev1.Secret{}).Name(),
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+		},
+		Data: map[string][]byte{"cool": []byte("secret")},
+	}
+}
+
+// An unmarshalableObject is a ConfigMap that cannot be marshalled to JSON.
+type unmarshalableObject struct {
+	*corev1.ConfigMap
+}
+
+func (o *unmarshalableObject) MarshalJSON() ([]byte, error) {
+	return nil, errBoom
+}
+
 type serviceModifier func(*corev1.Service)
 
 func sWithContainerPort(target int) serviceModifier {
 	return func(s *corev1.Service) {
 		s.Spec.Ports = append(s.Spec.Ports, corev1.ServicePort{
-			Name:       workloadName,
+			Name:       fmt.Sprintf("%s-%d", portName, target),
+			Protocol:   corev1.ProtocolTCP,
+			Port:       int32(target),
+			TargetPort: intstr.FromString(fmt.Sprintf("%s-%d", portName, target)),
+		})
+	}
+}
+
+func sWithUnnamedContainerPort(target int) serviceModifier {
+	return func(s *corev1.Service) {
+		s.Spec.Ports = append(s.Spec.Ports, corev1.ServicePort{
+			Name:       fmt.Sprintf("port-%d", target),
+			Protocol:   corev1.ProtocolTCP,
 			Port:       int32(target),
 			TargetPort: intstr.FromInt(target),
 		})
 	}
 }
 
+func sWithProtocolContainerPort(target int, protocol corev1.Protocol, name string) serviceModifier {
+	return func(s *corev1.Service) {
+		s.Spec.Ports = append(s.Spec.Ports, corev1.ServicePort{
+			Name:       name,
+			Protocol:   protocol,
+			Port:       int32(target),
+			TargetPort: intstr.FromString(fmt.Sprintf("%s-%d", portName, target)),
+		})
+	}
+}
+
+func sWithName(name string) serviceModifier {
+	return func(s *corev1.Service) {
+		s.SetName(name)
+	}
+}
+
+func sWithNodePort(port, nodePort int32) serviceModifier {
+	return func(s *corev1.Service) {
+		for i := range s.Spec.Ports {
+			if s.Spec.Ports[i].Port == port {
+				s.Spec.Ports[i].NodePort = nodePort
+			}
+		}
+	}
+}
+
+func sWithSessionAffinity(a corev1.ServiceAffinity, timeout *int32) serviceModifier {
+	return func(s *corev1.Service) {
+		s.Spec.SessionAffinity = a
+		if timeout != nil {
+			s.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: timeout}}
+		}
+	}
+}
+
+func sWithLoadBalancer(ip string, ranges ...string) serviceModifier {
+	return func(s *corev1.Service) {
+		s.Spec.LoadBalancerIP = ip
+		s.Spec.LoadBalancerSourceRanges = ranges
+	}
+}
+
+func sWithExternalTrafficPolicy(p corev1.ServiceExternalTrafficPolicyType) serviceModifier {
+	return func(s *corev1.Service) {
+		s.Spec.ExternalTrafficPolicy = p
+	}
+}
+
+func sWithType(t corev1.ServiceType) serviceModifier {
+	return func(s *corev1.Service) {
+		s.Spec.Type = t
+	}
+}
+
+func sWithClusterIP(ip string) serviceModifier {
+	return func(s *corev1.Service) {
+		s.Spec.ClusterIP = ip
+	}
+}
+
+func sWithSelector(labels map[string]string) serviceModifier {
+	return func(s *corev1.Service) {
+		for k, v := range labels {
+			s.Spec.Selector[k] = v
+		}
+	}
+}
+
+func sWithAnnotations(a map[string]string) serviceModifier {
+	return func(s *corev1.Service) {
+		s.SetAnnotations(a)
+	}
+}
+
 func service(mod ...serviceModifier) *corev1.Service {
 	s := &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
@@ -128,7 +345,7 @@ func service(mod ...serviceModifier) *corev1.Service {
 			APIVersion: serviceAPIVersion,
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name: workloadName,
+			Name: workloadName + ServiceNameSuffix,
 			Labels: map[string]string{
 				LabelKey: workloadUID,
 			},
@@ -137,7 +354,8 @@ func service(mod ...serviceModifier) *corev1.Service {
 			Selector: map[string]string{
 				LabelKey: workloadUID,
 			},
-			Type: corev1.ServiceTypeLoadBalancer,
+			Ports: []corev1.ServicePort{},
+			Type:  corev1.ServiceTypeLoadBalancer,
 		},
 	}
 
@@ -148,10 +366,42 @@ func service(mod ...serviceModifier) *corev1.Service {
 	return s
 }
 
+var _ workload.TranslationWrapper = NoopWrapper
+
+func TestNoopWrapper(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		o      []resource.Object
+	}{
+		"NilObject": {
+			reason: "Nil object should return nil.",
+		},
+		"Passthrough": {
+			reason: "Objects should be returned unchanged.",
+			o:      []resource.Object{deployment(dmWithContainerPorts(3000)), configMap()},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := NoopWrapper(context.Background(), &fake.Workload{}, tc.o)
+			if err != nil {
+				t.Errorf("\nReason: %s\nNoopWrapper(...): %s", tc.reason, err)
+			}
+
+			if diff := cmp.Diff(tc.o, r); diff != "" {
+				t.Errorf("\nReason: %s\nNoopWrapper(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 var _ workload.TranslationWrapper = KubeAppWrapper
 
 func TestKubeAppWrapper(t *testing.T) {
-	deployBytes, _ := json.Marshal(deployment())
+	deployBytes, _ := json.Marshal(deployment(dmWithNamespace(workloadNamespace)))
+	cmBytes, _ := json.Marshal(configMap())
+	secretBytes, _ := json.Marshal(secret())
 	type args struct {
 		w resource.Workload
 		o []resource.Object
@@ -174,6 +424,21 @@ func TestKubeAppWrapper(t *testing.T) {
 			},
 			want: want{},
 		},
+		"ErrorInvalidTemplateName": {
+			reason: "An object whose name cannot produce a valid resource template name should return an error.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+					},
+				},
+				o: []resource.Object{deployment(func(d *appsv1.Deployment) { d.SetName("Invalid_Name") })},
+			},
+			want: want{err: errors.Wrap(errors.Errorf(errFmtInvalidTemplate, "Invalid_Name-deployment",
+				strings.Join(validation.IsDNS1123Subdomain("Invalid_Name-deployment"), ", ")), errWrapInKubeApp)},
+		},
 		"SuccessfulWrapDeployment": {
 			reason: "A Deployment should be able to be wrapped in a KubernetesApplication.",
 			args: args{
@@ -210,6 +475,60 @@ func TestKubeAppWrapper(t *testing.T) {
 				},
 			}},
 			}},
+		"SuccessfulWrapMultipleKinds": {
+			reason: "Resource templates should be named after, and ordered by, the kind of the object they wrap.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+					},
+				},
+				o: []resource.Object{deployment(), configMap(), secret()},
+			},
+			want: want{result: []resource.Object{&workloadv1alpha1.KubernetesApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: workloadName,
+				},
+				Spec: workloadv1alpha1.KubernetesApplicationSpec{
+					ResourceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							LabelKey: workloadUID,
+						},
+					},
+					ResourceTemplates: []workloadv1alpha1.KubernetesApplicationResourceTemplate{
+						{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:   fmt.Sprintf("%s-%s", workloadName, "configmap"),
+								Labels: map[string]string{LabelKey: workloadUID},
+							},
+							Spec: workloadv1alpha1.KubernetesApplicationResourceSpec{
+								Template: runtime.RawExtension{Raw: cmBytes},
+							},
+						},
+						{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:   fmt.Sprintf("%s-%s", workloadName, "deployment"),
+								Labels: map[string]string{LabelKey: workloadUID},
+							},
+							Spec: workloadv1alpha1.KubernetesApplicationResourceSpec{
+								Template: runtime.RawExtension{Raw: deployBytes},
+							},
+						},
+						{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:   fmt.Sprintf("%s-%s", workloadName, "secret"),
+								Labels: map[string]string{LabelKey: workloadUID},
+							},
+							Spec: workloadv1alpha1.KubernetesApplicationResourceSpec{
+								Template: runtime.RawExtension{Raw: secretBytes},
+							},
+						},
+					},
+				},
+			}},
+			}},
 	}
 
 	for name, tc := range cases {
@@ -226,119 +545,2133 @@ func TestKubeAppWrapper(t *testing.T) {
 	}
 }
 
-var _ workload.TranslationWrapper = ServiceInjector
+func TestKubeAppWrapperMarshalError(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+	}
 
-func TestServiceInjector(t *testing.T) {
-	type args struct {
-		w resource.Workload
-		o []resource.Object
+	o := &unmarshalableObject{ConfigMap: configMap()}
+	_, merr := json.Marshal(o)
+	want := errors.Wrap(errors.Wrapf(merr, errFmtMarshalObject, "ConfigMap", workloadName), errWrapInKubeApp)
+
+	_, err := KubeAppWrapper(context.Background(), w, []resource.Object{o})
+	if diff := cmp.Diff(want, err, test.EquateErrors()); diff != "" {
+		t.Errorf("KubeAppWrapper(...): -want error, +got error:\n%s", diff)
 	}
+}
 
+func TestKubeAppWrapperClusterSelector(t *testing.T) {
 	type want struct {
-		result []resource.Object
-		err    error
+		sel *metav1.LabelSelector
+		err error
 	}
 
 	cases := map[string]struct {
-		reason string
-		args   args
-		want   want
+		reason     string
+		annotation string
+		want       want
 	}{
-		"NilObject": {
-			reason: "Nil object should immediately return nil.",
-			args: args{
-				w: &fake.Workload{},
-			},
-			want: want{},
-		},
-		"SuccessfulInjectService_1D_1C_1P": {
-			reason: "A Deployment with a port(s) should have a Service injected for first defined port.",
-			args: args{
-				w: &fake.Workload{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      workloadName,
-						Namespace: workloadNamespace,
-						UID:       types.UID(workloadUID),
-					},
-				},
-				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
-			},
-			want: want{result: []resource.Object{
-				deployment(dmWithContainerPorts(3000)),
-				service(sWithContainerPort(3000)),
+		"ValidSelector": {
+			reason:     "A valid cluster selector annotation should be used as the target selector.",
+			annotation: `{"matchLabels":{"region":"us-west"}}`,
+			want: want{sel: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"region": "us-west"},
 			}},
 		},
-		"SuccessfulInjectService_1D_1C_2P": {
-			reason: "A Deployment with a port(s) should have a Service injected for first defined port on the first container.",
-			args: args{
-				w: &fake.Workload{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      workloadName,
-						Namespace: workloadNamespace,
-						UID:       types.UID(workloadUID),
-					},
-				},
-				o: []resource.Object{deployment(dmWithContainerPorts(3000, 3001))},
-			},
-			want: want{result: []resource.Object{
-				deployment(dmWithContainerPorts(3000, 3001)),
-				service(sWithContainerPort(3000)),
-			}},
+		"InvalidSelector": {
+			reason:     "An invalid cluster selector annotation should return an error.",
+			annotation: `{"matchLabels":`,
+			want:       want{err: errors.Wrap(errors.New("unexpected end of JSON input"), errParseClusterSelector)},
 		},
-		"SuccessfulInjectService_2D_1C_1P": {
-			reason: "The first Deployment with a port(s) should have a Service injected for first defined port on the first container.",
-			args: args{
-				w: &fake.Workload{
-					ObjectMeta: metav1.ObjectMeta{
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := &fake.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        workloadName,
+					Namespace:   workloadNamespace,
+					UID:         types.UID(workloadUID),
+					Annotations: map[string]string{AnnotationKeyClusterSelector: tc.annotation},
+				},
+			}
+
+			r, err := KubeAppWrapper(context.Background(), w, []resource.Object{deployment()})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nKubeAppWrapper(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+
+			got := r[0].(*workloadv1alpha1.KubernetesApplication).Spec.TargetSelector
+			if diff := cmp.Diff(tc.want.sel, got); diff != "" {
+				t.Errorf("\nReason: %s\nKubeAppWrapper(...): -want selector, +got selector:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestKubeAppWrapperClusterTarget(t *testing.T) {
+	type want struct {
+		ref *workloadv1alpha1.KubernetesTargetReference
+		err error
+	}
+
+	cases := map[string]struct {
+		reason     string
+		annotation string
+		want       want
+	}{
+		"ValidTarget": {
+			reason:     "A valid cluster target annotation should be used as the target.",
+			annotation: workloadNamespace + "/remote-cluster",
+			want:       want{ref: &workloadv1alpha1.KubernetesTargetReference{Name: "remote-cluster"}},
+		},
+		"MissingNamespace": {
+			reason:     "A cluster target annotation without a namespace should return an error.",
+			annotation: "/remote-cluster",
+			want:       want{err: errors.Errorf(errFmtInvalidTarget, "/remote-cluster")},
+		},
+		"MissingName": {
+			reason:     "A cluster target annotation without a name should return an error.",
+			annotation: workloadNamespace,
+			want:       want{err: errors.Errorf(errFmtInvalidTarget, workloadNamespace)},
+		},
+		"OtherNamespace": {
+			reason:     "A cluster target in another namespace should return an error.",
+			annotation: "elsewhere/remote-cluster",
+			want:       want{err: errors.Errorf(errFmtTargetNamespace, "elsewhere/remote-cluster", workloadNamespace)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := &fake.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        workloadName,
+					Namespace:   workloadNamespace,
+					UID:         types.UID(workloadUID),
+					Annotations: map[string]string{AnnotationKeyClusterTarget: tc.annotation},
+				},
+			}
+
+			r, err := KubeAppWrapper(context.Background(), w, []resource.Object{deployment()})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nKubeAppWrapper(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+
+			got := r[0].(*workloadv1alpha1.KubernetesApplication).Spec.Target
+			if diff := cmp.Diff(tc.want.ref, got); diff != "" {
+				t.Errorf("\nReason: %s\nKubeAppWrapper(...): -want target, +got target:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestKubeAppWrapperLabels(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+	}
+
+	d := deployment()
+	d.SetLabels(map[string]string{"app": "foo", LabelKey: "not-the-workload-uid"})
+
+	r, err := KubeAppWrapper(context.Background(), w, []resource.Object{d})
+	if err != nil {
+		t.Fatalf("KubeAppWrapper(...): %s", err)
+	}
+
+	want := map[string]string{"app": "foo", LabelKey: workloadUID}
+	got := r[0].(*workloadv1alpha1.KubernetesApplication).Spec.ResourceTemplates[0].GetLabels()
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("KubeAppWrapper(...): -want labels, +got labels:\n%s", diff)
+	}
+}
+
+func TestKubeAppWrapperOrdering(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+	}
+
+	want, err := KubeAppWrapper(context.Background(), w, []resource.Object{configMap(), deployment(), secret()})
+	if err != nil {
+		t.Fatalf("KubeAppWrapper(...): %s", err)
+	}
+
+	shuffled := [][]resource.Object{
+		{configMap(), secret(), deployment()},
+		{deployment(), configMap(), secret()},
+		{deployment(), secret(), configMap()},
+		{secret(), configMap(), deployment()},
+		{secret(), deployment(), configMap()},
+	}
+
+	for _, objs := range shuffled {
+		got, err := KubeAppWrapper(context.Background(), w, objs)
+		if err != nil {
+			t.Fatalf("KubeAppWrapper(...): %s", err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("KubeAppWrapper(...): -want, +got:\n%s", diff)
+		}
+	}
+}
+
+func TestKubeAppWrapperNamespace(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      resource.Object
+		want   string
+	}{
+		"NoNamespace": {
+			reason: "An object without a namespace should be placed in the workload's namespace.",
+			o:      deployment(),
+			want:   workloadNamespace,
+		},
+		"ExistingNamespace": {
+			reason: "An object that is already in a namespace should be left in that namespace.",
+			o:      deployment(dmWithNamespace("cool-namespace")),
+			want:   "cool-namespace",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := KubeAppWrapper(context.Background(), w, []resource.Object{tc.o})
+			if err != nil {
+				t.Fatalf("KubeAppWrapper(...): %s", err)
+			}
+
+			app := r[0].(*workloadv1alpha1.KubernetesApplication)
+			got := &unstructured.Unstructured{}
+			if err := json.Unmarshal(app.Spec.ResourceTemplates[0].Spec.Template.Raw, got); err != nil {
+				t.Fatalf("json.Unmarshal(...): %s", err)
+			}
+
+			if diff := cmp.Diff(tc.want, got.GetNamespace()); diff != "" {
+				t.Errorf("\nReason: %s\nKubeAppWrapper(...): -want namespace, +got namespace:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestKubeAppWrapperPatches(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+	}
+
+	decode := func(s string) []jsonpatch.Operation {
+		p, err := jsonpatch.DecodePatch([]byte(s))
+		if err != nil {
+			t.Fatalf("jsonpatch.DecodePatch(...): %s", err)
+		}
+		return p
+	}
+
+	invalid := decode(`[{"op":"remove","path":"/spec/nonexistent"}]`)
+	d := deployment(dmWithNamespace(workloadNamespace))
+	b, _ := json.Marshal(d)
+	_, perr := jsonpatch.Patch(invalid).Apply(b)
+
+	type want struct {
+		annotations map[string]string
+		namespace   string
+		err         error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		patches []jsonpatch.Operation
+		want    want
+	}{
+		"NoPatches": {
+			reason: "Objects should be embedded unchanged when no patches are configured.",
+			want:   want{namespace: workloadNamespace},
+		},
+		"AddAndReplace": {
+			reason:  "Add and replace patches should mutate the embedded object.",
+			patches: decode(`[{"op":"add","path":"/metadata/annotations","value":{"cool":"very"}},{"op":"replace","path":"/metadata/namespace","value":"patched"}]`),
+			want: want{
+				annotations: map[string]string{"cool": "very"},
+				namespace:   "patched",
+			},
+		},
+		"InvalidPatch": {
+			reason:  "A patch that cannot be applied should return an error identifying the object.",
+			patches: invalid,
+			want:    want{err: errors.Wrap(errors.Wrapf(perr, errFmtPatchObject, deploymentKind, workloadName), errWrapInKubeApp)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := NewKubeAppWrapper(WithPatches(tc.patches...))(context.Background(), w, []resource.Object{deployment()})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nNewKubeAppWrapper(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+
+			app := r[0].(*workloadv1alpha1.KubernetesApplication)
+			got := &unstructured.Unstructured{}
+			if err := json.Unmarshal(app.Spec.ResourceTemplates[0].Spec.Template.Raw, got); err != nil {
+				t.Fatalf("json.Unmarshal(...): %s", err)
+			}
+
+			if diff := cmp.Diff(tc.want.annotations, got.GetAnnotations()); diff != "" {
+				t.Errorf("\nReason: %s\nNewKubeAppWrapper(...): -want annotations, +got annotations:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.namespace, got.GetNamespace()); diff != "" {
+				t.Errorf("\nReason: %s\nNewKubeAppWrapper(...): -want namespace, +got namespace:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestKubeAppWrapperTemplateAnnotations(t *testing.T) {
+	syncWave := "argocd.argoproj.io/sync-wave"
+
+	withAnnotations := func(a map[string]string) deploymentModifier {
+		return func(d *appsv1.Deployment) {
+			d.SetAnnotations(a)
+		}
+	}
+
+	type args struct {
+		prefixes    []string
+		annotations map[string]string
+		o           resource.Object
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   map[string]string
+	}{
+		"NotConfigured": {
+			reason: "Templates should not be annotated unless configured.",
+			args: args{
+				annotations: map[string]string{syncWave: "1"},
+				o:           deployment(),
+			},
+		},
+		"SyncWave": {
+			reason: "A workload sync wave annotation should survive wrapping.",
+			args: args{
+				prefixes:    []string{"argocd.argoproj.io/"},
+				annotations: map[string]string{syncWave: "1", "unrelated": "nope"},
+				o:           deployment(),
+			},
+			want: map[string]string{syncWave: "1"},
+		},
+		"Merged": {
+			reason: "Object annotations with a configured prefix should be merged with, and take precedence over, workload annotations.",
+			args: args{
+				prefixes:    []string{"argocd.argoproj.io/", "cool.io/"},
+				annotations: map[string]string{syncWave: "1", "argocd.argoproj.io/hook": "PreSync"},
+				o: deployment(withAnnotations(map[string]string{
+					syncWave:    "2",
+					"cool.io/a": "b",
+					"unrelated": "nope",
+				})),
+			},
+			want: map[string]string{syncWave: "2", "cool.io/a": "b", "argocd.argoproj.io/hook": "PreSync"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := &fake.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        workloadName,
+					Namespace:   workloadNamespace,
+					UID:         types.UID(workloadUID),
+					Annotations: tc.args.annotations,
+				},
+			}
+
+			r, err := NewKubeAppWrapper(WithTemplateAnnotations(tc.args.prefixes...))(context.Background(), w, []resource.Object{tc.args.o})
+			if err != nil {
+				t.Fatalf("NewKubeAppWrapper(...): %s", err)
+			}
+
+			got := r[0].(*workloadv1alpha1.KubernetesApplication).Spec.ResourceTemplates[0].GetAnnotations()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nNewKubeAppWrapper(...): -want annotations, +got annotations:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestKubeAppWrapperName(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+	}
+
+	long := strings.Repeat("a", 250) + "-"
+	invalid := func(name string) error {
+		return errors.Wrap(errors.Errorf(errFmtInvalidAppName, name, strings.Join(validation.IsDNS1123Subdomain(name), ", ")), errWrapInKubeApp)
+	}
+
+	type want struct {
+		name string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		options []WrapperOption
+		want    want
+	}{
+		"Default": {
+			reason: "The KubernetesApplication should be named after the workload by default.",
+			want:   want{name: workloadName},
+		},
+		"Prefix": {
+			reason:  "The KubernetesApplication name should be prefixed when configured.",
+			options: []WrapperOption{WithNamePrefix("oam-")},
+			want:    want{name: "oam-" + workloadName},
+		},
+		"Suffix": {
+			reason:  "The KubernetesApplication name should be suffixed when configured.",
+			options: []WrapperOption{WithNameSuffix("-app")},
+			want:    want{name: workloadName + "-app"},
+		},
+		"PrefixAndSuffix": {
+			reason:  "The KubernetesApplication name may be both prefixed and suffixed.",
+			options: []WrapperOption{WithNamePrefix("oam-"), WithNameSuffix("-app")},
+			want:    want{name: "oam-" + workloadName + "-app"},
+		},
+		"TooLong": {
+			reason:  "A prefix that makes the name longer than a DNS subdomain should return an error.",
+			options: []WrapperOption{WithNamePrefix(long)},
+			want:    want{err: invalid(long + workloadName)},
+		},
+		"Invalid": {
+			reason:  "A suffix that makes the name an invalid DNS subdomain should return an error.",
+			options: []WrapperOption{WithNameSuffix("_App")},
+			want:    want{err: invalid(workloadName + "_App")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewKubeAppWrapper(tc.options...)(context.Background(), w, []resource.Object{deployment()})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nNewKubeAppWrapper(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.name, got[0].GetName()); diff != "" {
+				t.Errorf("\nReason: %s\nNewKubeAppWrapper(...): -want name, +got name:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestKubeAppWrapperClusterScoped(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+	}
+
+	object := func(apiVersion, kind string) resource.Object {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetName("cool")
+		return u
+	}
+
+	widgets := schema.GroupVersion{Group: "example.org", Version: "v1"}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{widgets})
+	mapper.Add(widgets.WithKind("Widget"), meta.RESTScopeRoot)
+	mapper.Add(widgets.WithKind("Gadget"), meta.RESTScopeNamespace)
+
+	type args struct {
+		mapper meta.RESTMapper
+		objs   []resource.Object
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   map[string]string
+	}{
+		"WellKnownKinds": {
+			reason: "Well known cluster scoped objects should be wrapped without a namespace, while namespaced objects should be placed in the workload's namespace.",
+			args: args{
+				objs: []resource.Object{
+					object("apps/v1", "Deployment"),
+					object("rbac.authorization.k8s.io/v1", "ClusterRole"),
+					object("scheduling.k8s.io/v1", "PriorityClass"),
+				},
+			},
+			want: map[string]string{
+				"cool-deployment":    workloadNamespace,
+				"cool-clusterrole":   "",
+				"cool-priorityclass": "",
+			},
+		},
+		"RESTMapper": {
+			reason: "The configured RESTMapper should determine the scope of the kinds it knows, falling back to the well known cluster scoped kinds.",
+			args: args{
+				mapper: mapper,
+				objs: []resource.Object{
+					object("example.org/v1", "Widget"),
+					object("example.org/v1", "Gadget"),
+					object("scheduling.k8s.io/v1", "PriorityClass"),
+				},
+			},
+			want: map[string]string{
+				"cool-widget":        "",
+				"cool-gadget":        workloadNamespace,
+				"cool-priorityclass": "",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := NewKubeAppWrapper(WithRESTMapper(tc.args.mapper))(context.Background(), w, tc.args.objs)
+			if err != nil {
+				t.Fatalf("NewKubeAppWrapper(...): %s", err)
+			}
+
+			got := map[string]string{}
+			for _, kart := range r[0].(*workloadv1alpha1.KubernetesApplication).Spec.ResourceTemplates {
+				m := &metav1.PartialObjectMetadata{}
+				if err := json.Unmarshal(kart.Spec.Template.Raw, m); err != nil {
+					t.Fatalf("json.Unmarshal(...): %s", err)
+				}
+				got[kart.GetName()] = m.GetNamespace()
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nNewKubeAppWrapper(...): -want template namespaces, +got template namespaces:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTemplateName(t *testing.T) {
+	type args struct {
+		name string
+		kind string
+	}
+
+	type want struct {
+		name string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Short": {
+			reason: "A short name should be suffixed with its lowercase kind.",
+			args:   args{name: workloadName, kind: "Deployment"},
+			want:   want{name: workloadName + "-deployment"},
+		},
+		"Long": {
+			reason: "A long name should be truncated and hashed to produce a valid DNS subdomain.",
+			args:   args{name: strings.Repeat("a", 300), kind: "Deployment"},
+			want:   want{name: strings.Repeat("a", 233) + "-9835fa6b-deployment"},
+		},
+		"Invalid": {
+			reason: "A name that cannot produce a valid DNS subdomain should return an error.",
+			args:   args{name: "Invalid_Name", kind: "Deployment"},
+			want: want{err: errors.Errorf(errFmtInvalidTemplate, "Invalid_Name-deployment",
+				strings.Join(validation.IsDNS1123Subdomain("Invalid_Name-deployment"), ", "))},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := templateName(tc.args.name, tc.args.kind)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\ntemplateName(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.name, got); diff != "" {
+				t.Errorf("\nReason: %s\ntemplateName(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if err == nil && len(validation.IsDNS1123Subdomain(got)) > 0 {
+				t.Errorf("\nReason: %s\ntemplateName(...): %q is not a valid DNS subdomain", tc.reason, got)
+			}
+		})
+	}
+}
+
+func TestServiceName(t *testing.T) {
+	type want struct {
+		name string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		name   string
+		want   want
+	}{
+		"Short": {
+			reason: "A short name should be suffixed.",
+			name:   workloadName,
+			want:   want{name: workloadName + ServiceNameSuffix},
+		},
+		"Long": {
+			reason: "A long name should be truncated and hashed to produce a valid DNS-1035 label.",
+			name:   strings.Repeat("a", 100),
+			want:   want{name: strings.Repeat("a", 50) + "-28165978" + ServiceNameSuffix},
+		},
+		"Invalid": {
+			reason: "A name that cannot produce a valid DNS-1035 label should return an error.",
+			name:   "1-invalid",
+			want: want{err: errors.Errorf(errFmtInvalidServiceName, "1-invalid-svc",
+				strings.Join(validation.IsDNS1035Label("1-invalid-svc"), ", "))},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := serviceName(tc.name)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nserviceName(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.name, got); diff != "" {
+				t.Errorf("\nReason: %s\nserviceName(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if err == nil && len(validation.IsDNS1035Label(got)) > 0 {
+				t.Errorf("\nReason: %s\nserviceName(...): %q is not a valid DNS-1035 label", tc.reason, got)
+			}
+		})
+	}
+}
+
+var _ workload.TranslationWrapper = ServiceInjector
+
+func TestServiceInjector(t *testing.T) {
+	type args struct {
+		w resource.Workload
+		o []resource.Object
+	}
+
+	type want struct {
+		result []resource.Object
+		err    error
+	}
+
+	timeout := int32(600)
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NilObject": {
+			reason: "Nil object should immediately return nil.",
+			args: args{
+				w: &fake.Workload{},
+			},
+			want: want{},
+		},
+		"NoServiceForJob": {
+			reason: "A Job runs to completion, so its pods should not be exposed by a Service.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+					},
+				},
+				o: []resource.Object{&batchv1.Job{Spec: batchv1.JobSpec{Template: deployment(dmWithContainerPorts(3000)).Spec.Template}}},
+			},
+			want: want{result: []resource.Object{
+				&batchv1.Job{Spec: batchv1.JobSpec{Template: deployment(dmWithContainerPorts(3000)).Spec.Template}},
+			}},
+		},
+		"SuccessfulInjectService_1D_1C_1P": {
+			reason: "A Deployment with a named port should have a Service injected that targets that port by name.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithContainerPorts(3000)),
+				service(sWithContainerPort(3000)),
+			}},
+		},
+		"SuccessfulInjectService_1D_1C_2P": {
+			reason: "A Deployment with multiple ports should have a Service injected for every defined port.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000, 3001))},
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithContainerPorts(3000, 3001)),
+				service(sWithContainerPort(3000), sWithContainerPort(3001)),
+			}},
+		},
+		"SuccessfulInjectService_2D_1C_1P": {
+			reason: "Only the first Deployment with a port(s) should have a Service injected.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+					},
+				},
+				o: []resource.Object{
+					deployment(dmWithContainerPorts(4000)),
+					deployment(dmWithContainerPorts(3000)),
+				},
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithContainerPorts(4000)),
+				deployment(dmWithContainerPorts(3000)),
+				service(sWithContainerPort(4000)),
+			}},
+		},
+		"SuccessfulInjectService_2D_2C_2P": {
+			reason: "The first Deployment with a port(s) should have a Service injected for every port on every container.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+					},
+				},
+				o: []resource.Object{
+					deployment(dmWithContainerPorts(3000, 3001), dmWithContainerPorts(4000, 4001)),
+					deployment(dmWithContainerPorts(5000, 5001), dmWithContainerPorts(6000, 6001)),
+				},
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithContainerPorts(3000, 3001), dmWithContainerPorts(4000, 4001)),
+				deployment(dmWithContainerPorts(5000, 5001), dmWithContainerPorts(6000, 6001)),
+				service(sWithContainerPort(3000), sWithContainerPort(3001), sWithContainerPort(4000), sWithContainerPort(4001)),
+			}},
+		},
+		"SuccessfulInjectService_StatefulSet": {
+			reason: "A StatefulSet with a port(s) should have a Service injected.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+					},
+				},
+				o: []resource.Object{statefulSet(dmWithContainerPorts(3000))},
+			},
+			want: want{result: []resource.Object{
+				statefulSet(dmWithContainerPorts(3000)),
+				service(sWithContainerPort(3000)),
+			}},
+		},
+		"SuccessfulInjectService_DaemonSet": {
+			reason: "A DaemonSet with a port(s) should have a Service injected.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+					},
+				},
+				o: []resource.Object{daemonSet(dmWithContainerPorts(3000))},
+			},
+			want: want{result: []resource.Object{
+				daemonSet(dmWithContainerPorts(3000)),
+				service(sWithContainerPort(3000)),
+			}},
+		},
+		"SuccessfulInjectService_UnnamedPorts": {
+			reason: "Ports without a name should be assigned a name derived from, and target, their port number.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+					},
+				},
+				o: []resource.Object{deployment(dmWithUnnamedContainerPorts(3000, 3001))},
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithUnnamedContainerPorts(3000, 3001)),
+				service(sWithUnnamedContainerPort(3000), sWithUnnamedContainerPort(3001)),
+			}},
+		},
+		"SuccessfulInjectService_DuplicatePorts": {
+			reason: "Ports declared by more than one container should only be exposed once.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000, 3001), dmWithContainerPorts(3001, 3002))},
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithContainerPorts(3000, 3001), dmWithContainerPorts(3001, 3002)),
+				service(sWithContainerPort(3000), sWithContainerPort(3001), sWithContainerPort(3002)),
+			}},
+		},
+		"SuccessfulInjectService_UDP": {
+			reason: "The protocol of a container port should be carried over to the Service port.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+					},
+				},
+				o: []resource.Object{deployment(dmWithProtocolContainerPorts(corev1.ProtocolUDP, 53))},
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithProtocolContainerPorts(corev1.ProtocolUDP, 53)),
+				service(sWithProtocolContainerPort(53, corev1.ProtocolUDP, "test-port-53")),
+			}},
+		},
+		"SuccessfulInjectService_TCPAndUDP": {
+			reason: "A port number exposed using multiple protocols should result in a protocol qualified Service port for each protocol.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+					},
+				},
+				o: []resource.Object{deployment(
+					dmWithProtocolContainerPorts(corev1.ProtocolTCP, 53),
+					dmWithProtocolContainerPorts(corev1.ProtocolUDP, 53),
+				)},
+			},
+			want: want{result: []resource.Object{
+				deployment(
+					dmWithProtocolContainerPorts(corev1.ProtocolTCP, 53),
+					dmWithProtocolContainerPorts(corev1.ProtocolUDP, 53),
+				),
+				service(
+					sWithProtocolContainerPort(53, corev1.ProtocolTCP, "test-port-53-tcp"),
+					sWithProtocolContainerPort(53, corev1.ProtocolUDP, "test-port-53-udp"),
+				),
+			}},
+		},
+		"SuccessfulInjectService_ClusterIP": {
+			reason: "A workload annotated with the ClusterIP service type should have a ClusterIP Service injected.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        workloadName,
+						Namespace:   workloadNamespace,
+						UID:         types.UID(workloadUID),
+						Annotations: map[string]string{AnnotationKeyServiceType: string(corev1.ServiceTypeClusterIP)},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithContainerPorts(3000)),
+				service(sWithContainerPort(3000), sWithType(corev1.ServiceTypeClusterIP)),
+			}},
+		},
+		"SuccessfulInjectService_NodePort": {
+			reason: "A workload annotated with the NodePort service type should have a NodePort Service injected.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        workloadName,
+						Namespace:   workloadNamespace,
+						UID:         types.UID(workloadUID),
+						Annotations: map[string]string{AnnotationKeyServiceType: string(corev1.ServiceTypeNodePort)},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithContainerPorts(3000)),
+				service(sWithContainerPort(3000), sWithType(corev1.ServiceTypeNodePort)),
+			}},
+		},
+		"SuccessfulInjectService_FixedNodePort": {
+			reason: "A NodePort Service should use the node ports requested by the workload's annotation.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+						Annotations: map[string]string{
+							AnnotationKeyServiceType: string(corev1.ServiceTypeNodePort),
+							AnnotationKeyNodePort:    "3000:30080, 5000:30500",
+						},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000, 4000))},
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithContainerPorts(3000, 4000)),
+				service(
+					sWithContainerPort(3000),
+					sWithContainerPort(4000),
+					sWithType(corev1.ServiceTypeNodePort),
+					sWithNodePort(3000, 30080),
+				),
+			}},
+		},
+		"SuccessfulInjectService_SessionAffinity": {
+			reason: "A workload annotated with ClientIP session affinity should have a Service with ClientIP session affinity injected.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        workloadName,
+						Namespace:   workloadNamespace,
+						UID:         types.UID(workloadUID),
+						Annotations: map[string]string{AnnotationKeySessionAffinity: string(corev1.ServiceAffinityClientIP)},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithContainerPorts(3000)),
+				service(sWithContainerPort(3000), sWithSessionAffinity(corev1.ServiceAffinityClientIP, nil)),
+			}},
+		},
+		"SuccessfulInjectService_SessionAffinityTimeout": {
+			reason: "A workload annotated with ClientIP session affinity and a timeout should have a Service with that timeout injected.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+						Annotations: map[string]string{
+							AnnotationKeySessionAffinity:        string(corev1.ServiceAffinityClientIP),
+							AnnotationKeySessionAffinityTimeout: "600",
+						},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithContainerPorts(3000)),
+				service(sWithContainerPort(3000), sWithSessionAffinity(corev1.ServiceAffinityClientIP, &timeout)),
+			}},
+		},
+		"SuccessfulInjectService_LoadBalancer": {
+			reason: "A workload annotated with a load balancer IP and source ranges should have a LoadBalancer Service using them injected.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+						Annotations: map[string]string{
+							AnnotationKeyLoadBalancerIP:           "203.0.113.10",
+							AnnotationKeyLoadBalancerSourceRanges: "10.0.0.0/8, 2001:db8::/32",
+						},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(80, 443))},
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithContainerPorts(80, 443)),
+				service(sWithContainerPort(80), sWithContainerPort(443), sWithLoadBalancer("203.0.113.10", "10.0.0.0/8", "2001:db8::/32")),
+			}},
+		},
+		"SuccessfulInjectService_ExternalTrafficPolicyLocal": {
+			reason: "A workload annotated with the Local external traffic policy should have a Service using it injected.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        workloadName,
+						Namespace:   workloadNamespace,
+						UID:         types.UID(workloadUID),
+						Annotations: map[string]string{AnnotationKeyExternalTrafficPolicy: "Local"},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithContainerPorts(3000)),
+				service(sWithContainerPort(3000), sWithExternalTrafficPolicy(corev1.ServiceExternalTrafficPolicyTypeLocal)),
+			}},
+		},
+		"SuccessfulInjectService_ExternalTrafficPolicyCluster": {
+			reason: "A NodePort workload annotated with the Cluster external traffic policy should have a Service using it injected.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+						Annotations: map[string]string{
+							AnnotationKeyServiceType:           string(corev1.ServiceTypeNodePort),
+							AnnotationKeyExternalTrafficPolicy: "Cluster",
+						},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithContainerPorts(3000)),
+				service(
+					sWithContainerPort(3000),
+					sWithType(corev1.ServiceTypeNodePort),
+					sWithExternalTrafficPolicy(corev1.ServiceExternalTrafficPolicyTypeCluster),
+				),
+			}},
+		},
+		"SuccessfulInjectService_Headless": {
+			reason: "A workload annotated as headless should have a headless ClusterIP Service injected.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        workloadName,
+						Namespace:   workloadNamespace,
+						UID:         types.UID(workloadUID),
+						Annotations: map[string]string{AnnotationKeyHeadless: "true"},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithContainerPorts(3000)),
+				service(sWithContainerPort(3000), sWithType(corev1.ServiceTypeClusterIP), sWithClusterIP(corev1.ClusterIPNone)),
+			}},
+		},
+		"SuccessfulInjectService_HeadlessClusterIP": {
+			reason: "A workload annotated as headless and ClusterIP should have a headless ClusterIP Service injected.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+						Annotations: map[string]string{
+							AnnotationKeyHeadless:    "true",
+							AnnotationKeyServiceType: string(corev1.ServiceTypeClusterIP),
+						},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithContainerPorts(3000)),
+				service(sWithContainerPort(3000), sWithType(corev1.ServiceTypeClusterIP), sWithClusterIP(corev1.ClusterIPNone)),
+			}},
+		},
+		"SuccessfulInjectService_PodLabels": {
+			reason: "The injected Service should select all pod template labels, with the workload label taking precedence.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+					},
+				},
+				o: []resource.Object{deployment(
+					dmWithContainerPorts(3000),
+					dmWithPodLabels(map[string]string{"app": "coolapp", LabelKey: "not-the-workload-uid"}),
+				)},
+			},
+			want: want{result: []resource.Object{
+				deployment(
+					dmWithContainerPorts(3000),
+					dmWithPodLabels(map[string]string{"app": "coolapp", LabelKey: "not-the-workload-uid"}),
+				),
+				service(sWithContainerPort(3000), sWithSelector(map[string]string{"app": "coolapp"})),
+			}},
+		},
+		"SuccessfulInjectService_PerContainer": {
+			reason: "A Service should be injected for each container with ports when the workload asks for a Service per container.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        workloadName,
+						Namespace:   workloadNamespace,
+						UID:         types.UID(workloadUID),
+						Annotations: map[string]string{AnnotationKeyServicePerContainer: "true"},
+					},
+				},
+				o: []resource.Object{deployment(
+					dmWithNamedContainerPorts("frontend", 3000, 3001),
+					dmWithNamedContainerPorts("sidecar", 4000),
+					dmWithNamedContainerPorts("portless"),
+				)},
+			},
+			want: want{result: []resource.Object{
+				deployment(
+					dmWithNamedContainerPorts("frontend", 3000, 3001),
+					dmWithNamedContainerPorts("sidecar", 4000),
+					dmWithNamedContainerPorts("portless"),
+				),
+				service(sWithName(workloadName+"-frontend"), sWithContainerPort(3000), sWithContainerPort(3001)),
+				service(sWithName(workloadName+"-sidecar"), sWithContainerPort(4000)),
+			}},
+		},
+		"SuccessfulInjectService_NamedContainer": {
+			reason: "A workload that names a container should have a Service injected that exposes only that container's ports.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        workloadName,
+						Namespace:   workloadNamespace,
+						UID:         types.UID(workloadUID),
+						Annotations: map[string]string{AnnotationKeyServiceContainer: "app"},
+					},
+				},
+				o: []resource.Object{deployment(dmWithNamedContainerPorts("proxy", 15001), dmWithNamedContainerPorts("app", 3000))},
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithNamedContainerPorts("proxy", 15001), dmWithNamedContainerPorts("app", 3000)),
+				service(sWithContainerPort(3000)),
+			}},
+		},
+		"SuccessfulInjectService_Annotations": {
+			reason: "Only workload annotations with a Service prefix should be propagated to the injected Service.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+						Annotations: map[string]string{
+							"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+							"service.oam.dev/owner":                                 "team-a",
+							"example.org/unrelated":                                 "ignored",
+							AnnotationKeyServiceType:                                string(corev1.ServiceTypeLoadBalancer),
+						},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithContainerPorts(3000)),
+				service(sWithContainerPort(3000), sWithAnnotations(map[string]string{
+					"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+					"service.oam.dev/owner":                                 "team-a",
+				})),
+			}},
+		},
+		"NoServiceAnnotation": {
+			reason: "No Service should be injected for a workload that opts out of Service injection.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        workloadName,
+						Namespace:   workloadNamespace,
+						UID:         types.UID(workloadUID),
+						Annotations: map[string]string{AnnotationKeyNoService: "true"},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{result: []resource.Object{deployment(dmWithContainerPorts(3000))}},
+		},
+		"ServiceAlreadyPresent": {
+			reason: "No Service should be injected if one that selects the workload already exists.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
 						Name:      workloadName,
 						Namespace: workloadNamespace,
 						UID:       types.UID(workloadUID),
 					},
 				},
 				o: []resource.Object{
-					deployment(dmWithContainerPorts(4000)),
 					deployment(dmWithContainerPorts(3000)),
+					service(sWithContainerPort(4000)),
+				},
+			},
+			want: want{result: []resource.Object{
+				deployment(dmWithContainerPorts(3000)),
+				service(sWithContainerPort(4000)),
+			}},
+		},
+		"NoContainers": {
+			reason: "No Service should be injected for a Deployment whose pod template has no containers.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+					},
+				},
+				o: []resource.Object{deployment()},
+			},
+			want: want{result: []resource.Object{deployment()}},
+		},
+		"ZeroValueTemplate": {
+			reason: "No Service should be injected for a Deployment with a zero value pod template.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+					},
+				},
+				o: []resource.Object{&appsv1.Deployment{}},
+			},
+			want: want{result: []resource.Object{&appsv1.Deployment{}}},
+		},
+		"NilDeployment": {
+			reason: "No Service should be injected for a nil Deployment.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+					},
+				},
+				o: []resource.Object{(*appsv1.Deployment)(nil)},
+			},
+			want: want{result: []resource.Object{(*appsv1.Deployment)(nil)}},
+		},
+		"ErrorHeadlessLoadBalancer": {
+			reason: "A workload annotated as both headless and LoadBalancer should return an error.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+						Annotations: map[string]string{
+							AnnotationKeyHeadless:    "true",
+							AnnotationKeyServiceType: string(corev1.ServiceTypeLoadBalancer),
+						},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{err: errors.New(errHeadlessServiceType)},
+		},
+		"ErrorInvalidServiceType": {
+			reason: "A workload annotated with an unknown service type should return an error.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        workloadName,
+						Namespace:   workloadNamespace,
+						UID:         types.UID(workloadUID),
+						Annotations: map[string]string{AnnotationKeyServiceType: "ExternalName"},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{err: errors.Errorf(errFmtInvalidServiceType, "ExternalName")},
+		},
+		"ErrorInvalidSessionAffinity": {
+			reason: "A workload annotated with an unknown session affinity should return an error.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        workloadName,
+						Namespace:   workloadNamespace,
+						UID:         types.UID(workloadUID),
+						Annotations: map[string]string{AnnotationKeySessionAffinity: "Cookie"},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{err: errors.Errorf(errFmtInvalidSessionAffinity, "Cookie")},
+		},
+		"ErrorSessionAffinityTimeoutNotPositive": {
+			reason: "A workload annotated with a session affinity timeout that is not positive should return an error.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+						Annotations: map[string]string{
+							AnnotationKeySessionAffinity:        string(corev1.ServiceAffinityClientIP),
+							AnnotationKeySessionAffinityTimeout: "0",
+						},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{err: errors.Errorf(errFmtSessionAffinityTimeoutSecs, 0, maxSessionAffinityTimeout)},
+		},
+		"ErrorSessionAffinityTimeoutWithoutClientIP": {
+			reason: "A workload annotated with a session affinity timeout but not ClientIP session affinity should return an error.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        workloadName,
+						Namespace:   workloadNamespace,
+						UID:         types.UID(workloadUID),
+						Annotations: map[string]string{AnnotationKeySessionAffinityTimeout: "600"},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{err: errors.New(errTimeoutWithoutClientIP)},
+		},
+		"ErrorNoSuchServiceContainer": {
+			reason: "A workload that names a container that does not exist should return an error.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        workloadName,
+						Namespace:   workloadNamespace,
+						UID:         types.UID(workloadUID),
+						Annotations: map[string]string{AnnotationKeyServiceContainer: "app"},
+					},
+				},
+				o: []resource.Object{deployment(dmWithNamedContainerPorts("proxy", 15001))},
+			},
+			want: want{err: errors.Errorf(errFmtNoServiceContainer, "app")},
+		},
+		"ErrorServiceContainerNoPorts": {
+			reason: "A workload that names a container that declares no ports should return an error.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        workloadName,
+						Namespace:   workloadNamespace,
+						UID:         types.UID(workloadUID),
+						Annotations: map[string]string{AnnotationKeyServiceContainer: "app"},
+					},
+				},
+				o: []resource.Object{deployment(dmWithNamedContainerPorts("proxy", 15001), dmWithNamedContainerPorts("app"))},
+			},
+			want: want{err: errors.Errorf(errFmtNoContainerPorts, "app")},
+		},
+		"ErrorInvalidLoadBalancerSourceRange": {
+			reason: "A workload annotated with a malformed load balancer source range should return an error.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        workloadName,
+						Namespace:   workloadNamespace,
+						UID:         types.UID(workloadUID),
+						Annotations: map[string]string{AnnotationKeyLoadBalancerSourceRanges: "10.0.0.0/8,10.0.0.1"},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(80))},
+			},
+			want: want{err: errors.Errorf(errFmtInvalidSourceRange, "10.0.0.1")},
+		},
+		"ErrorInvalidLoadBalancerIP": {
+			reason: "A workload annotated with a malformed load balancer IP should return an error.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        workloadName,
+						Namespace:   workloadNamespace,
+						UID:         types.UID(workloadUID),
+						Annotations: map[string]string{AnnotationKeyLoadBalancerIP: "lb.example.org"},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(80))},
+			},
+			want: want{err: errors.Errorf(errFmtInvalidLBIP, "lb.example.org")},
+		},
+		"ErrorLoadBalancerSettingsClusterIP": {
+			reason: "A workload annotated with load balancer settings for a ClusterIP Service should return an error.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+						Annotations: map[string]string{
+							AnnotationKeyServiceType:    string(corev1.ServiceTypeClusterIP),
+							AnnotationKeyLoadBalancerIP: "203.0.113.10",
+						},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(80))},
+			},
+			want: want{err: errors.Errorf(errFmtLoadBalancerType, corev1.ServiceTypeClusterIP)},
+		},
+		"ErrorExternalTrafficPolicyClusterIP": {
+			reason: "A ClusterIP workload annotated with an external traffic policy should return an error.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+						Annotations: map[string]string{
+							AnnotationKeyServiceType:           string(corev1.ServiceTypeClusterIP),
+							AnnotationKeyExternalTrafficPolicy: "Local",
+						},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{err: errors.Errorf(errFmtTrafficPolicyType, corev1.ServiceTypeClusterIP)},
+		},
+		"ErrorInvalidExternalTrafficPolicy": {
+			reason: "A workload annotated with an unknown external traffic policy should return an error.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        workloadName,
+						Namespace:   workloadNamespace,
+						UID:         types.UID(workloadUID),
+						Annotations: map[string]string{AnnotationKeyExternalTrafficPolicy: "Nearest"},
+					},
 				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{err: errors.Errorf(errFmtInvalidTraffic, "Nearest")},
+		},
+		"ErrorNodePortOutOfRange": {
+			reason: "A workload requesting a node port outside the node port range should return an error.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+						Annotations: map[string]string{
+							AnnotationKeyServiceType: string(corev1.ServiceTypeNodePort),
+							AnnotationKeyNodePort:    "3000:8080",
+						},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{err: errors.Errorf(errFmtNodePortRange, 8080, 3000, minNodePort, maxNodePort)},
+		},
+		"ErrorInvalidNodePort": {
+			reason: "A workload with a malformed node port annotation should return an error.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+						Annotations: map[string]string{
+							AnnotationKeyServiceType: string(corev1.ServiceTypeNodePort),
+							AnnotationKeyNodePort:    "3000",
+						},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{err: errors.Errorf(errFmtInvalidNodePort, "3000")},
+		},
+		"ErrorNodePortClusterIP": {
+			reason: "A workload requesting node ports for a ClusterIP Service should return an error.",
+			args: args{
+				w: &fake.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+						Annotations: map[string]string{
+							AnnotationKeyServiceType: string(corev1.ServiceTypeClusterIP),
+							AnnotationKeyNodePort:    "3000:30080",
+						},
+					},
+				},
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{err: errors.Errorf(errFmtNodePortType, corev1.ServiceTypeClusterIP)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := ServiceInjector(context.Background(), tc.args.w, tc.args.o)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nServiceInjector(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, r); diff != "" {
+				t.Errorf("\nReason: %s\nServiceInjector(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestServiceInjectorHostNetwork(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+	}
+
+	hostNetwork := func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.HostNetwork = true
+		d.Spec.Template.Spec.Containers[0].Ports[1].HostPort = 8443
+	}
+
+	cases := map[string]struct {
+		reason string
+		d      *appsv1.Deployment
+		want   []intstr.IntOrString
+	}{
+		"PodNetwork": {
+			reason: "Named container ports of pods that use the pod network should be targeted by name.",
+			d:      deployment(dmWithContainerPorts(80, 443)),
+			want:   []intstr.IntOrString{intstr.FromString(portName + "-80"), intstr.FromString(portName + "-443")},
+		},
+		"HostNetwork": {
+			reason: "Container ports of pods that use the host network should be targeted by host port number.",
+			d:      deployment(dmWithContainerPorts(80, 443), hostNetwork),
+			want:   []intstr.IntOrString{intstr.FromInt(80), intstr.FromInt(8443)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ServiceInjector(context.Background(), w, []resource.Object{tc.d})
+			if err != nil {
+				t.Fatalf("ServiceInjector(...): %s", err)
+			}
+
+			targets := []intstr.IntOrString{}
+			for _, p := range got[1].(*corev1.Service).Spec.Ports {
+				targets = append(targets, p.TargetPort)
+			}
+			if diff := cmp.Diff(tc.want, targets); diff != "" {
+				t.Errorf("\nReason: %s\nServiceInjector(...): -want target ports, +got target ports:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestServiceInjectorOrder(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+	}
+
+	// Supply objects with spare capacity in order to detect whether the
+	// injector appends to the caller's backing array.
+	backing := make([]resource.Object, 3, 4)
+	backing[0] = configMap()
+	backing[1] = deployment(dmWithContainerPorts(3000))
+	backing[2] = secret()
+	spare := backing[:4]
+
+	got, err := ServiceInjector(context.Background(), w, backing)
+	if err != nil {
+		t.Fatalf("ServiceInjector(...): %s", err)
+	}
+
+	want := []resource.Object{
+		configMap(),
+		deployment(dmWithContainerPorts(3000)),
+		secret(),
+		service(sWithContainerPort(3000)),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ServiceInjector(...): -want, +got:\n%s", diff)
+	}
+	if spare[3] != nil {
+		t.Errorf("ServiceInjector(...): the Service should not be appended to the caller's slice")
+	}
+}
+
+func TestServiceInjectorPortNamer(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+	}
+
+	type want struct {
+		names []string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		pn     PortNamer
+		want   want
+	}{
+		"CustomNamer": {
+			reason: "Service ports should be named by the supplied PortNamer.",
+			pn: func(container string, p corev1.ContainerPort, index int) string {
+				return fmt.Sprintf("web-%d-%d", index, p.ContainerPort)
 			},
-			want: want{result: []resource.Object{
-				deployment(dmWithContainerPorts(4000)),
-				deployment(dmWithContainerPorts(3000)),
-				service(sWithContainerPort(4000)),
+			want: want{names: []string{"web-0-80", "web-1-443"}},
+		},
+		"NameTooLong": {
+			reason: "A PortNamer that produces a name longer than an IANA service name should return an error.",
+			pn: func(container string, p corev1.ContainerPort, index int) string {
+				return container + "-port"
+			},
+			want: want{err: errors.Errorf(errFmtInvalidPortName, containerName+"-port",
+				strings.Join(validation.IsValidPortName(containerName+"-port"), ", "))},
+		},
+		"DuplicateName": {
+			reason: "A PortNamer that produces the same name for two ports should return an error.",
+			pn: func(container string, p corev1.ContainerPort, index int) string {
+				return "web"
+			},
+			want: want{err: errors.Errorf(errFmtDuplicatePortName, "web")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewServiceInjector(WithPortNamer(tc.pn))(context.Background(), w, []resource.Object{deployment(dmWithContainerPorts(80, 443))})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nNewServiceInjector(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+
+			names := []string{}
+			for _, p := range got[1].(*corev1.Service).Spec.Ports {
+				names = append(names, p.Name)
+			}
+			if diff := cmp.Diff(tc.want.names, names); diff != "" {
+				t.Errorf("\nReason: %s\nNewServiceInjector(...): -want port names, +got port names:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestContainerPortNamer(t *testing.T) {
+	type args struct {
+		container string
+		p         corev1.ContainerPort
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   string
+	}{
+		"Named": {
+			reason: "A named port should be prefixed with its container name.",
+			args:   args{container: "web", p: corev1.ContainerPort{Name: "http", ContainerPort: 8080}},
+			want:   "web-http",
+		},
+		"Unnamed": {
+			reason: "An unnamed TCP port should be named after its container and number.",
+			args:   args{container: "web", p: corev1.ContainerPort{ContainerPort: 8080, Protocol: corev1.ProtocolTCP}},
+			want:   "web-8080",
+		},
+		"UnnamedUDP": {
+			reason: "An unnamed UDP port should be suffixed with its protocol.",
+			args:   args{container: "dns", p: corev1.ContainerPort{ContainerPort: 53, Protocol: corev1.ProtocolUDP}},
+			want:   "dns-53-udp",
+		},
+		"Long": {
+			reason: "A name longer than an IANA service name should be truncated and hashed, without consecutive hyphens.",
+			args:   args{container: "istio-pod", p: corev1.ContainerPort{Name: "metrics", ContainerPort: 9090}},
+			want:   "istio-pod-80e5",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ContainerPortNamer(tc.args.container, tc.args.p, 0)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nContainerPortNamer(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if errs := validation.IsValidPortName(got); len(errs) > 0 {
+				t.Errorf("\nReason: %s\nContainerPortNamer(...): invalid port name %q: %s", tc.reason, got, strings.Join(errs, ", "))
+			}
+		})
+	}
+}
+
+func TestServiceInjectorPrefixPortNames(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        workloadName,
+			Namespace:   workloadNamespace,
+			UID:         types.UID(workloadUID),
+			Annotations: map[string]string{AnnotationKeyPrefixPortNames: "true"},
+		},
+	}
+
+	withPorts := func(container string, ports ...corev1.ContainerPort) deploymentModifier {
+		return func(d *appsv1.Deployment) {
+			d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{Name: container, Ports: ports})
+		}
+	}
+
+	d := deployment(
+		withPorts("web", corev1.ContainerPort{Name: "http", ContainerPort: 8080}, corev1.ContainerPort{ContainerPort: 8443}),
+		withPorts("sidecar", corev1.ContainerPort{Name: "http", ContainerPort: 15001}),
+	)
+
+	// A configured PortNamer should be overridden by the annotation.
+	got, err := NewServiceInjector(WithPortNamer(func(string, corev1.ContainerPort, int) string { return "nope" }))(context.Background(), w, []resource.Object{d})
+	if err != nil {
+		t.Fatalf("NewServiceInjector(...): %s", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("NewServiceInjector(...): want a Deployment and a single Service, got %d objects", len(got))
+	}
+
+	want := []corev1.ServicePort{
+		{Name: "web-http", Protocol: corev1.ProtocolTCP, Port: 8080, TargetPort: intstr.FromString("http")},
+		{Name: "web-8443", Protocol: corev1.ProtocolTCP, Port: 8443, TargetPort: intstr.FromInt(8443)},
+		{Name: "sidecar-http", Protocol: corev1.ProtocolTCP, Port: 15001, TargetPort: intstr.FromString("http")},
+	}
+	if diff := cmp.Diff(want, got[1].(*corev1.Service).Spec.Ports); diff != "" {
+		t.Errorf("NewServiceInjector(...): -want ports, +got ports:\n%s", diff)
+	}
+}
+
+func TestServiceInjectorAppProtocols(t *testing.T) {
+	withPort := func(p corev1.ContainerPort) deploymentModifier {
+		return func(d *appsv1.Deployment) {
+			d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{Name: containerName, Ports: []corev1.ContainerPort{p}})
+		}
+	}
+
+	type args struct {
+		annotations map[string]string
+		o           []WrapperOption
+		p           corev1.ContainerPort
+	}
+	type want struct {
+		ports []corev1.ServicePort
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"HTTP": {
+			reason: "A port named http should keep its name, which already implies its application protocol.",
+			args:   args{p: corev1.ContainerPort{Name: "http", ContainerPort: 8080}},
+			want: want{ports: []corev1.ServicePort{
+				{Name: "http", Protocol: corev1.ProtocolTCP, Port: 8080, TargetPort: intstr.FromString("http")},
 			}},
 		},
-		"SuccessfulInjectService_2D_2C_2P": {
-			reason: "The first Deployment with a port(s) should have a Service injected for first defined port on the first container.",
+		"HTTP2": {
+			reason: "A port named http2 should keep its name, which already implies its application protocol.",
+			args:   args{p: corev1.ContainerPort{Name: "http2", ContainerPort: 8080}},
+			want: want{ports: []corev1.ServicePort{
+				{Name: "http2", Protocol: corev1.ProtocolTCP, Port: 8080, TargetPort: intstr.FromString("http2")},
+			}},
+		},
+		"GRPC": {
+			reason: "A port named grpc should keep its name and its TCP protocol.",
+			args:   args{p: corev1.ContainerPort{Name: "grpc", ContainerPort: 9090}},
+			want: want{ports: []corev1.ServicePort{
+				{Name: "grpc", Protocol: corev1.ProtocolTCP, Port: 9090, TargetPort: intstr.FromString("grpc")},
+			}},
+		},
+		"HTTPS": {
+			reason: "A port named https should keep its name, which already implies its application protocol.",
+			args:   args{p: corev1.ContainerPort{Name: "https", ContainerPort: 8443}},
+			want: want{ports: []corev1.ServicePort{
+				{Name: "https", Protocol: corev1.ProtocolTCP, Port: 8443, TargetPort: intstr.FromString("https")},
+			}},
+		},
+		"UnknownName": {
+			reason: "A port with an unknown name should have no application protocol, and keep its name.",
+			args:   args{p: corev1.ContainerPort{Name: "metrics", ContainerPort: 9102}},
+			want: want{ports: []corev1.ServicePort{
+				{Name: "metrics", Protocol: corev1.ProtocolTCP, Port: 9102, TargetPort: intstr.FromString("metrics")},
+			}},
+		},
+		"Unnamed": {
+			reason: "An unnamed port should have no application protocol, and keep its generated name.",
+			args:   args{p: corev1.ContainerPort{ContainerPort: 8080}},
+			want: want{ports: []corev1.ServicePort{
+				{Name: "port-8080", Protocol: corev1.ProtocolTCP, Port: 8080, TargetPort: intstr.FromInt(8080)},
+			}},
+		},
+		"CustomMapping": {
+			reason: "A port should be named after the application protocol the configured mapping associates with its name.",
 			args: args{
-				w: &fake.Workload{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      workloadName,
-						Namespace: workloadNamespace,
-						UID:       types.UID(workloadUID),
-					},
+				o: []WrapperOption{WithAppProtocols(map[string]string{"api": "grpc"})},
+				p: corev1.ContainerPort{Name: "api", ContainerPort: 9090},
+			},
+			want: want{ports: []corev1.ServicePort{
+				{Name: "grpc-api", Protocol: corev1.ProtocolTCP, Port: 9090, TargetPort: intstr.FromString("api")},
+			}},
+		},
+		"Override": {
+			reason: "The application protocol set by the annotation should take precedence over that implied by a port's name.",
+			args: args{
+				annotations: map[string]string{AnnotationKeyAppProtocol: "8080:http2"},
+				p:           corev1.ContainerPort{Name: "http", ContainerPort: 8080},
+			},
+			want: want{ports: []corev1.ServicePort{
+				{Name: "http2-http", Protocol: corev1.ProtocolTCP, Port: 8080, TargetPort: intstr.FromString("http")},
+			}},
+		},
+		"OverrideAlreadyNamed": {
+			reason: "A port already named after the application protocol set by the annotation should keep its name.",
+			args: args{
+				annotations: map[string]string{AnnotationKeyAppProtocol: "9090:grpc"},
+				p:           corev1.ContainerPort{Name: "grpc-api", ContainerPort: 9090},
+			},
+			want: want{ports: []corev1.ServicePort{
+				{Name: "grpc-api", Protocol: corev1.ProtocolTCP, Port: 9090, TargetPort: intstr.FromString("grpc-api")},
+			}},
+		},
+		"InvalidOverride": {
+			reason: "An annotation that is not a list of <port>:<app-protocol> pairs should return an error.",
+			args: args{
+				annotations: map[string]string{AnnotationKeyAppProtocol: "grpc"},
+				p:           corev1.ContainerPort{Name: "api", ContainerPort: 9090},
+			},
+			want: want{err: errors.Errorf(errFmtInvalidAppProtocol, "grpc")},
+		},
+		"PortNamer": {
+			reason: "A port named by a PortNamer should keep the name it was given.",
+			args: args{
+				annotations: map[string]string{AnnotationKeyAppProtocol: "9090:grpc"},
+				o:           []WrapperOption{WithPortNamer(func(string, corev1.ContainerPort, int) string { return "api" })},
+				p:           corev1.ContainerPort{Name: "grpc", ContainerPort: 9090},
+			},
+			want: want{ports: []corev1.ServicePort{
+				{Name: "api", Protocol: corev1.ProtocolTCP, Port: 9090, TargetPort: intstr.FromString("grpc")},
+			}},
+		},
+		"PrefixPortNames": {
+			reason: "A port named using the prefix port names annotation should keep the name it was given.",
+			args: args{
+				annotations: map[string]string{AnnotationKeyPrefixPortNames: "true"},
+				p:           corev1.ContainerPort{Name: "grpc", ContainerPort: 9090},
+			},
+			want: want{ports: []corev1.ServicePort{
+				{Name: ContainerPortNamer(containerName, corev1.ContainerPort{Name: "grpc"}, 0), Protocol: corev1.ProtocolTCP, Port: 9090, TargetPort: intstr.FromString("grpc")},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := &fake.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        workloadName,
+					Namespace:   workloadNamespace,
+					UID:         types.UID(workloadUID),
+					Annotations: tc.args.annotations,
 				},
-				o: []resource.Object{
-					deployment(dmWithContainerPorts(3000, 3001), dmWithContainerPorts(4000, 4001)),
-					deployment(dmWithContainerPorts(5000, 5001), dmWithContainerPorts(6000, 6001)),
+			}
+
+			got, err := NewServiceInjector(tc.args.o...)(context.Background(), w, []resource.Object{deployment(withPort(tc.args.p))})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nNewServiceInjector(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.ports, got[1].(*corev1.Service).Spec.Ports); diff != "" {
+				t.Errorf("\nReason: %s\nNewServiceInjector(...): -want ports, +got ports:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestServiceInjectorWarnings(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+	}
+
+	withPorts := func(container string, ports ...corev1.ContainerPort) deploymentModifier {
+		return func(d *appsv1.Deployment) {
+			d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{Name: container, Ports: ports})
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		d      *appsv1.Deployment
+		want   []Warning
+	}{
+		"DuplicateWithinContainer": {
+			reason: "A port dropped in favour of a differently named port of the same container should be warned about.",
+			d: deployment(withPorts("cool",
+				corev1.ContainerPort{Name: "http", ContainerPort: 8080},
+				corev1.ContainerPort{Name: "web", ContainerPort: 8080, Protocol: corev1.ProtocolTCP},
+				corev1.ContainerPort{Name: "metrics", ContainerPort: 8080, Protocol: corev1.ProtocolUDP},
+			)),
+			want: []Warning{{
+				Field:  "containers[cool].ports[web]",
+				Reason: fmt.Sprintf(reasonFmtDuplicatePort, 8080, corev1.ProtocolTCP, "http"),
+			}},
+		},
+		"DuplicateAcrossContainers": {
+			reason: "A port dropped in favour of a differently named port of another container should be warned about.",
+			d: deployment(
+				withPorts("cool", corev1.ContainerPort{Name: "http", ContainerPort: 8080}),
+				withPorts("sidecar", corev1.ContainerPort{Name: "proxy", ContainerPort: 8080}),
+			),
+			want: []Warning{{
+				Field:  "containers[sidecar].ports[proxy]",
+				Reason: fmt.Sprintf(reasonFmtDuplicatePort, 8080, corev1.ProtocolTCP, "http"),
+			}},
+		},
+		"IdenticalDuplicate": {
+			reason: "A port dropped in favour of an identically named port should not be warned about.",
+			d: deployment(
+				withPorts("cool", corev1.ContainerPort{Name: "http", ContainerPort: 8080}),
+				withPorts("sidecar", corev1.ContainerPort{Name: "http", ContainerPort: 8080}),
+			),
+			want: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &WarningRecorder{}
+			if _, err := ServiceInjector(WithWarningRecorder(context.Background(), r), w, []resource.Object{tc.d}); err != nil {
+				t.Fatalf("ServiceInjector(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want, r.Warnings()); diff != "" {
+				t.Errorf("\nReason: %s\nServiceInjector(...): -want warnings, +got warnings:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestServiceInjectorLoadBalancerSupport(t *testing.T) {
+	type args struct {
+		supported   bool
+		annotations map[string]string
+	}
+	type want struct {
+		spec     corev1.ServiceSpec
+		nodePort int32
+		warnings []Warning
+	}
+
+	downgraded := []Warning{{
+		Field:  "metadata.annotations[" + AnnotationKeyServiceType + "]",
+		Reason: reasonLoadBalancerDowngraded,
+	}}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Supported": {
+			reason: "A LoadBalancer Service should be injected by default when load balancers are supported.",
+			args:   args{supported: true},
+			want:   want{spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer}},
+		},
+		"UnsupportedDefaultType": {
+			reason: "The default LoadBalancer Service should be downgraded to a NodePort Service, with a warning, when load balancers are unsupported.",
+			args:   args{supported: false},
+			want: want{
+				spec:     corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort},
+				warnings: downgraded,
+			},
+		},
+		"UnsupportedWithLoadBalancerSettings": {
+			reason: "Load balancer settings should be dropped when a LoadBalancer Service is downgraded, while node ports and traffic policy are kept.",
+			args: args{
+				supported: false,
+				annotations: map[string]string{
+					AnnotationKeyServiceType:              string(corev1.ServiceTypeLoadBalancer),
+					AnnotationKeyLoadBalancerIP:           "10.0.0.1",
+					AnnotationKeyLoadBalancerSourceRanges: "10.0.0.0/8",
+					AnnotationKeyNodePort:                 "80:30080",
+					AnnotationKeyExternalTrafficPolicy:    string(corev1.ServiceExternalTrafficPolicyTypeLocal),
+				},
+			},
+			want: want{
+				spec: corev1.ServiceSpec{
+					Type:                  corev1.ServiceTypeNodePort,
+					ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
 				},
+				nodePort: 30080,
+				warnings: downgraded,
+			},
+		},
+		"UnsupportedClusterIP": {
+			reason: "A ClusterIP Service should not be changed, or warned about, when load balancers are unsupported.",
+			args: args{
+				supported:   false,
+				annotations: map[string]string{AnnotationKeyServiceType: string(corev1.ServiceTypeClusterIP)},
 			},
+			want: want{spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := &fake.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        workloadName,
+					Namespace:   workloadNamespace,
+					UID:         types.UID(workloadUID),
+					Annotations: tc.args.annotations,
+				},
+			}
+
+			r := &WarningRecorder{}
+			ctx := WithWarningRecorder(context.Background(), r)
+			got, err := NewServiceInjector(WithLoadBalancerSupport(tc.args.supported))(ctx, w, []resource.Object{deployment(dmWithContainerPorts(80))})
+			if err != nil {
+				t.Fatalf("NewServiceInjector(...): %s", err)
+			}
+
+			// Only the settings affected by the downgrade are compared.
+			spec := got[1].(*corev1.Service).Spec
+			if diff := cmp.Diff(tc.want.nodePort, spec.Ports[0].NodePort); diff != "" {
+				t.Errorf("\nReason: %s\nNewServiceInjector(...): -want node port, +got node port:\n%s", tc.reason, diff)
+			}
+			spec.Selector, spec.Ports = nil, nil
+			if diff := cmp.Diff(tc.want.spec, spec); diff != "" {
+				t.Errorf("\nReason: %s\nNewServiceInjector(...): -want Service spec, +got Service spec:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.warnings, r.Warnings()); diff != "" {
+				t.Errorf("\nReason: %s\nNewServiceInjector(...): -want warnings, +got warnings:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestStrict(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+	}
+
+	type want struct {
+		result []resource.Object
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		wrap   workload.TranslationWrapper
+		objs   []resource.Object
+		want   want
+	}{
+		"ServiceInjectorLenient": {
+			reason: "By default ServiceInjector should return objects it does not understand unchanged.",
+			wrap:   NewServiceInjector(),
+			objs:   []resource.Object{configMap()},
+			want:   want{result: []resource.Object{configMap()}},
+		},
+		"ServiceInjectorStrict": {
+			reason: "In strict mode ServiceInjector should return an error when supplied no object with a pod template.",
+			wrap:   NewServiceInjector(WithStrict()),
+			objs:   []resource.Object{configMap()},
+			want:   want{err: ErrNoTranslatableObject},
+		},
+		"ServiceInjectorStrictNil": {
+			reason: "In strict mode ServiceInjector should return an error when supplied no objects.",
+			wrap:   NewServiceInjector(WithStrict()),
+			objs:   nil,
+			want:   want{err: ErrNoTranslatableObject},
+		},
+		"ServiceInjectorStrictSuccess": {
+			reason: "In strict mode ServiceInjector should inject a Service when supplied an object with a pod template.",
+			wrap:   NewServiceInjector(WithStrict()),
+			objs:   []resource.Object{deployment(dmWithContainerPorts(3000))},
 			want: want{result: []resource.Object{
-				deployment(dmWithContainerPorts(3000, 3001), dmWithContainerPorts(4000, 4001)),
-				deployment(dmWithContainerPorts(5000, 5001), dmWithContainerPorts(6000, 6001)),
+				deployment(dmWithContainerPorts(3000)),
 				service(sWithContainerPort(3000)),
 			}},
 		},
+		"KubeAppWrapperLenient": {
+			reason: "By default KubeAppWrapper should return nil when supplied no objects.",
+			wrap:   NewKubeAppWrapper(),
+			objs:   nil,
+			want:   want{result: nil},
+		},
+		"KubeAppWrapperStrict": {
+			reason: "In strict mode KubeAppWrapper should return an error when supplied no objects.",
+			wrap:   NewKubeAppWrapper(WithStrict()),
+			objs:   []resource.Object{},
+			want:   want{err: ErrNoTranslatableObject},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			r, err := ServiceInjector(context.Background(), tc.args.w, tc.args.o)
-
+			r, err := tc.wrap(context.Background(), w, tc.objs)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("\nReason: %s\nServiceInjector(...): -want error, +got error:\n%s", tc.reason, diff)
+				t.Errorf("\nReason: %s\nWrap(...): -want error, +got error:\n%s", tc.reason, diff)
 			}
-
 			if diff := cmp.Diff(tc.want.result, r); diff != "" {
-				t.Errorf("\nReason: %s\nServiceInjector(...): -want, +got:\n%s", tc.reason, diff)
+				t.Errorf("\nReason: %s\nWrap(...): -want, +got:\n%s", tc.reason, diff)
 			}
 		})
 	}
@@ -0,0 +1,168 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const errFmtMutationHook = "mutation hook %s failed"
+
+// A Translator runs a series of TranslationWrappers in order, passing the
+// objects produced by each wrapper to the next.
+type Translator struct {
+	stages   []stage
+	hooks    []stage
+	observer StageObserver
+}
+
+// A stage of a Translator.
+type stage struct {
+	name string
+	wrap workload.TranslationWrapper
+	hook bool
+}
+
+// A MutationHook mutates the objects produced by a Translator's wrappers, for
+// example to apply organisation specific conventions supplied by a plugin
+// without modifying the built in wrappers. It returns the mutated objects, or
+// an error that aborts the translation.
+type MutationHook func(ctx context.Context, w resource.Workload, objs []resource.Object) ([]resource.Object, error)
+
+// A StageObserver observes each stage run by a Translator. It may be used to
+// record metrics about the translation pipeline.
+type StageObserver interface {
+	// ObserveStage is called once each time a stage is run, with the name of
+	// the stage, how long it took to run, and how many objects it returned.
+	// It is called even if the stage returned an error.
+	ObserveStage(name string, d time.Duration, objects int)
+}
+
+// A StageObserverFn is a function that satisfies StageObserver.
+type StageObserverFn func(name string, d time.Duration, objects int)
+
+// ObserveStage calls the StageObserverFn.
+func (fn StageObserverFn) ObserveStage(name string, d time.Duration, objects int) {
+	fn(name, d, objects)
+}
+
+// A NopStageObserver does nothing.
+type NopStageObserver struct{}
+
+// ObserveStage does nothing.
+func (o NopStageObserver) ObserveStage(_ string, _ time.Duration, _ int) {}
+
+// A TranslatorOption configures a Translator.
+type TranslatorOption func(*Translator)
+
+// WithWrappers appends the supplied TranslationWrappers to those run by a
+// Translator. Wrappers are run in the order they are supplied, so for example
+// ServiceInjector should be supplied before KubeAppWrapper in order for the
+// injected Service to be wrapped.
+//
+// Each wrapper's stage is named after the function that implements it, for
+// example KubeAppWrapper. Use WithNamedWrapper to supply a more meaningful
+// name for wrappers that are closures.
+func WithWrappers(w ...workload.TranslationWrapper) TranslatorOption {
+	return func(t *Translator) {
+		for _, wrap := range w {
+			t.stages = append(t.stages, stage{name: funcName(wrap), wrap: wrap})
+		}
+	}
+}
+
+// WithNamedWrapper appends the supplied TranslationWrapper to those run by a
+// Translator, naming its stage as supplied.
+func WithNamedWrapper(name string, w workload.TranslationWrapper) TranslatorOption {
+	return func(t *Translator) {
+		t.stages = append(t.stages, stage{name: name, wrap: w})
+	}
+}
+
+// WithMutationHooks appends the supplied MutationHooks to those run by a
+// Translator. Hooks are run in the order they are supplied, after all of the
+// Translator's wrappers, regardless of the order in which options are
+// supplied. Each hook is observed as a stage named after the function that
+// implements it.
+func WithMutationHooks(h ...MutationHook) TranslatorOption {
+	return func(t *Translator) {
+		for _, hook := range h {
+			t.hooks = append(t.hooks, stage{name: funcName(hook), wrap: workload.TranslationWrapper(hook), hook: true})
+		}
+	}
+}
+
+// WithStageObserver configures the StageObserver a Translator notifies after
+// running each stage. Stages are not observed by default.
+func WithStageObserver(o StageObserver) TranslatorOption {
+	return func(t *Translator) {
+		t.observer = o
+	}
+}
+
+// NewTranslator returns a Translator that runs no wrappers unless configured
+// otherwise.
+func NewTranslator(o ...TranslatorOption) *Translator {
+	t := &Translator{observer: NopStageObserver{}}
+	for _, fn := range o {
+		fn(t)
+	}
+	return t
+}
+
+// Wrap runs each of the Translator's wrappers in order, followed by each of
+// its MutationHooks, feeding the objects returned by each into the next. It
+// returns the first error encountered, and stops early if the supplied context
+// is cancelled. Wrap satisfies workload.TranslationWrapper, so a Translator may
+// itself be used as a wrapper.
+func (t *Translator) Wrap(ctx context.Context, w resource.Workload, objs []resource.Object) ([]resource.Object, error) {
+	stages := make([]stage, 0, len(t.stages)+len(t.hooks))
+	stages = append(stages, t.stages...)
+	stages = append(stages, t.hooks...)
+	for _, s := range stages {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var err error
+		start := time.Now()
+		objs, err = s.wrap(ctx, w, objs)
+		t.observer.ObserveStage(s.name, time.Since(start), len(objs))
+		if err != nil && s.hook {
+			return nil, errors.Wrapf(err, errFmtMutationHook, s.name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return objs, nil
+}
+
+// funcName returns the unqualified name of the supplied function, for example
+// KubeAppWrapper or NewServiceInjector.func1.
+func funcName(fn interface{}) string {
+	n := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	return n[strings.LastIndex(n, "/")+1:]
+}
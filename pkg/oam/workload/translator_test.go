@@ -0,0 +1,377 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/oam/workload"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ workload.TranslationWrapper = NewTranslator().Wrap
+
+// appendWrapper returns a TranslationWrapper that appends the supplied object.
+func appendWrapper(o resource.Object) workload.TranslationWrapper {
+	return func(_ context.Context, _ resource.Workload, objs []resource.Object) ([]resource.Object, error) {
+		return append(objs, o), nil
+	}
+}
+
+// errorWrapper is a TranslationWrapper that always returns errBoom.
+func errorWrapper(_ context.Context, _ resource.Workload, _ []resource.Object) ([]resource.Object, error) {
+	return nil, errBoom
+}
+
+func TestTranslator(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+	}
+
+	type args struct {
+		w resource.Workload
+		o []resource.Object
+	}
+
+	type want struct {
+		result []resource.Object
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		t      *Translator
+		args   args
+		want   want
+	}{
+		"NoWrappers": {
+			reason: "A Translator with no wrappers should return its input unchanged.",
+			t:      NewTranslator(),
+			args: args{
+				w: w,
+				o: []resource.Object{deployment()},
+			},
+			want: want{result: []resource.Object{deployment()}},
+		},
+		"WrappersRunInOrder": {
+			reason: "Each wrapper should be passed the output of the wrapper before it.",
+			t:      NewTranslator(WithWrappers(appendWrapper(configMap()), appendWrapper(secret()))),
+			args: args{
+				w: w,
+				o: []resource.Object{deployment()},
+			},
+			want: want{result: []resource.Object{deployment(), configMap(), secret()}},
+		},
+		"InjectThenWrap": {
+			reason: "A Service injected before wrapping should be wrapped.",
+			t:      NewTranslator(WithWrappers(ServiceInjector, KubeAppWrapper)),
+			args: args{
+				w: w,
+				o: []resource.Object{deployment(dmWithContainerPorts(3000))},
+			},
+			want: want{result: func() []resource.Object {
+				objs, _ := ServiceInjector(context.Background(), w, []resource.Object{deployment(dmWithContainerPorts(3000))})
+				objs, _ = KubeAppWrapper(context.Background(), w, objs)
+				return objs
+			}()},
+		},
+		"ErrorShortCircuits": {
+			reason: "Wrappers after one that returns an error should not be run.",
+			t: NewTranslator(WithWrappers(errorWrapper, func(_ context.Context, _ resource.Workload, _ []resource.Object) ([]resource.Object, error) {
+				t.Errorf("wrapper called after an error was returned")
+				return nil, nil
+			})),
+			args: args{
+				w: w,
+				o: []resource.Object{deployment()},
+			},
+			want: want{err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := tc.t.Wrap(context.Background(), tc.args.w, tc.args.o)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nWrap(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, r); diff != "" {
+				t.Errorf("\nReason: %s\nWrap(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// An observation made by a StageObserver, less its duration.
+type observation struct {
+	Name    string
+	Objects int
+}
+
+// A fakeObserver records the stages it observes.
+type fakeObserver struct {
+	observed []observation
+}
+
+func (o *fakeObserver) ObserveStage(name string, _ time.Duration, objects int) {
+	o.observed = append(o.observed, observation{Name: name, Objects: objects})
+}
+
+func TestTranslatorStageObserver(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+	}
+
+	cases := map[string]struct {
+		reason  string
+		options []TranslatorOption
+		want    []observation
+	}{
+		"ObservedOncePerStage": {
+			reason: "Each stage should be observed once, with its name and the number of objects it returned.",
+			options: []TranslatorOption{
+				WithNamedWrapper("append-configmap", appendWrapper(configMap())),
+				WithWrappers(NoopWrapper, KubeAppWrapper),
+			},
+			want: []observation{
+				{Name: "append-configmap", Objects: 2},
+				{Name: "workload.NoopWrapper", Objects: 2},
+				{Name: "workload.KubeAppWrapper", Objects: 1},
+			},
+		},
+		"ObservedUntilError": {
+			reason: "A stage that returns an error should be observed, but subsequent stages should not be run.",
+			options: []TranslatorOption{
+				WithNamedWrapper("boom", errorWrapper),
+				WithWrappers(NoopWrapper),
+			},
+			want: []observation{
+				{Name: "boom", Objects: 0},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := &fakeObserver{}
+			tr := NewTranslator(append(tc.options, WithStageObserver(o))...)
+			_, _ = tr.Wrap(context.Background(), w, []resource.Object{deployment()})
+
+			if diff := cmp.Diff(tc.want, o.observed); diff != "" {
+				t.Errorf("\nReason: %s\nWrap(...): -want observations, +got observations:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// labelHook is a MutationHook that labels every object.
+func labelHook(_ context.Context, _ resource.Workload, objs []resource.Object) ([]resource.Object, error) {
+	for _, o := range objs {
+		l := o.GetLabels()
+		if l == nil {
+			l = map[string]string{}
+		}
+		l["example.org/team"] = "cool"
+		o.SetLabels(l)
+	}
+	return objs, nil
+}
+
+// errorHook is a MutationHook that always returns errBoom.
+func errorHook(_ context.Context, _ resource.Workload, _ []resource.Object) ([]resource.Object, error) {
+	return nil, errBoom
+}
+
+func TestTranslatorMutationHooks(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+	}
+
+	labelled := func(o resource.Object) resource.Object {
+		o.SetLabels(map[string]string{"example.org/team": "cool"})
+		return o
+	}
+
+	type want struct {
+		result []resource.Object
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		t      *Translator
+		want   want
+	}{
+		"HooksRunAfterWrappers": {
+			reason: "Hooks should mutate the objects produced by every wrapper, even if supplied before them.",
+			t: NewTranslator(
+				WithMutationHooks(labelHook),
+				WithWrappers(appendWrapper(configMap())),
+			),
+			want: want{result: []resource.Object{labelled(deployment()), labelled(configMap())}},
+		},
+		"ErrorAborts": {
+			reason: "A hook that returns an error should abort the translation.",
+			t: NewTranslator(
+				WithWrappers(appendWrapper(configMap())),
+				WithMutationHooks(errorHook, func(_ context.Context, _ resource.Workload, _ []resource.Object) ([]resource.Object, error) {
+					t.Errorf("hook called after an error was returned")
+					return nil, nil
+				}),
+			),
+			want: want{err: errors.Wrapf(errBoom, errFmtMutationHook, "workload.errorHook")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := tc.t.Wrap(context.Background(), w, []resource.Object{deployment()})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nWrap(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, r); diff != "" {
+				t.Errorf("\nReason: %s\nWrap(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTranslatorIdempotent(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        workloadName,
+			Namespace:   workloadNamespace,
+			UID:         types.UID(workloadUID),
+			Annotations: map[string]string{AnnotationKeyMetricsPort: portName + "-3000"},
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		t      *Translator
+	}{
+		"ServiceInjector": {
+			reason: "Injecting a Service into objects that already include one should be a no-op.",
+			t:      NewTranslator(WithWrappers(ServiceInjector)),
+		},
+		"ServiceMonitorInjector": {
+			reason: "Injecting a ServiceMonitor into objects that already include one should be a no-op.",
+			t:      NewTranslator(WithWrappers(ServiceInjector, ServiceMonitorInjector)),
+		},
+		"KubeAppWrapper": {
+			reason: "Wrapping objects that have already been wrapped should be a no-op.",
+			t:      NewTranslator(WithWrappers(KubeAppWrapper)),
+		},
+		"FullPipeline": {
+			reason: "Translating already translated objects should be a no-op.",
+			t:      NewTranslator(WithWrappers(ServiceInjector, ServiceMonitorInjector, KubeAppWrapper)),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			once, err := tc.t.Wrap(context.Background(), w, []resource.Object{deployment(dmWithContainerPorts(3000))})
+			if err != nil {
+				t.Fatalf("Wrap(...): %s", err)
+			}
+			twice, err := tc.t.Wrap(context.Background(), w, once)
+			if err != nil {
+				t.Fatalf("Wrap(Wrap(...)): %s", err)
+			}
+			if diff := cmp.Diff(once, twice); diff != "" {
+				t.Errorf("\nReason: %s\nWrap(Wrap(...)): -once, +twice:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCancelled(t *testing.T) {
+	w := &fake.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        workloadName,
+			Namespace:   workloadNamespace,
+			UID:         types.UID(workloadUID),
+			Annotations: map[string]string{AnnotationKeyMetricsPort: portName + "-3000"},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cases := map[string]struct {
+		reason string
+		wrap   workload.TranslationWrapper
+	}{
+		"Translator": {
+			reason: "A Translator should not run any stages once its context is cancelled.",
+			wrap: NewTranslator(WithWrappers(func(_ context.Context, _ resource.Workload, _ []resource.Object) ([]resource.Object, error) {
+				t.Errorf("wrapper called after the context was cancelled")
+				return nil, nil
+			})).Wrap,
+		},
+		"KubeAppWrapper": {
+			reason: "KubeAppWrapper should stop wrapping objects once its context is cancelled.",
+			wrap:   KubeAppWrapper,
+		},
+		"ServiceInjector": {
+			reason: "ServiceInjector should stop inspecting objects once its context is cancelled.",
+			wrap:   ServiceInjector,
+		},
+		"ServiceMonitorInjector": {
+			reason: "ServiceMonitorInjector should return early if its context is cancelled.",
+			wrap:   ServiceMonitorInjector,
+		},
+		"DedupeWrapper": {
+			reason: "DedupeWrapper should return early if its context is cancelled.",
+			wrap:   DedupeWrapper,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := tc.wrap(ctx, w, []resource.Object{deployment(dmWithContainerPorts(3000)), service(sWithContainerPort(3000))})
+			if diff := cmp.Diff(context.Canceled, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nWrap(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if r != nil {
+				t.Errorf("\nReason: %s\nWrap(...): want nil objects, got %d", tc.reason, len(r))
+			}
+		})
+	}
+}
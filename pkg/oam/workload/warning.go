@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"sync"
+)
+
+// A Warning describes part of a workload that could not be translated, and
+// was therefore dropped.
+type Warning struct {
+	// Field that could not be translated, e.g. spec.containers[name].config.
+	Field string
+
+	// Reason the field could not be translated.
+	Reason string
+}
+
+// String returns a human readable description of the Warning.
+func (w Warning) String() string {
+	return w.Field + ": " + w.Reason
+}
+
+// A WarningRecorder collects the Warnings raised while translating a
+// workload. It is safe for concurrent use.
+type WarningRecorder struct {
+	mx       sync.Mutex
+	warnings []Warning
+}
+
+// Record the supplied Warning.
+func (r *WarningRecorder) Record(w Warning) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	r.warnings = append(r.warnings, w)
+}
+
+// Warnings returns the recorded Warnings, in the order they were recorded.
+func (r *WarningRecorder) Warnings() []Warning {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	if r.warnings == nil {
+		return nil
+	}
+	out := make([]Warning, len(r.warnings))
+	copy(out, r.warnings)
+	return out
+}
+
+type warningRecorderKey struct{}
+
+// WithWarningRecorder returns a copy of the supplied context that carries the
+// supplied WarningRecorder. Translators and TranslationWrappers passed the
+// returned context record their Warnings to the recorder, allowing the caller
+// to surface them, for example as events or status conditions.
+func WithWarningRecorder(ctx context.Context, r *WarningRecorder) context.Context {
+	return context.WithValue(ctx, warningRecorderKey{}, r)
+}
+
+// RecordWarning records the supplied Warning to the WarningRecorder carried by
+// the supplied context. The Warning is discarded if the context carries no
+// WarningRecorder.
+func RecordWarning(ctx context.Context, w Warning) {
+	r, ok := ctx.Value(warningRecorderKey{}).(*WarningRecorder)
+	if !ok {
+		return
+	}
+	r.Record(w)
+}
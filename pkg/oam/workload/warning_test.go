@@ -0,0 +1,53 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRecordWarning(t *testing.T) {
+	first := Warning{Field: "spec.first", Reason: "first is not supported"}
+	second := Warning{Field: "spec.second", Reason: "second is not supported"}
+
+	t.Run("NoRecorder", func(t *testing.T) {
+		// Recording a warning to a context with no recorder should not panic.
+		RecordWarning(context.Background(), first)
+	})
+
+	t.Run("Recorder", func(t *testing.T) {
+		r := &WarningRecorder{}
+		ctx := WithWarningRecorder(context.Background(), r)
+		RecordWarning(ctx, first)
+		RecordWarning(ctx, second)
+
+		want := []Warning{first, second}
+		if diff := cmp.Diff(want, r.Warnings()); diff != "" {
+			t.Errorf("Warnings(): -want, +got:\n%s", diff)
+		}
+	})
+
+	t.Run("String", func(t *testing.T) {
+		want := "spec.first: first is not supported"
+		if diff := cmp.Diff(want, first.String()); diff != "" {
+			t.Errorf("String(): -want, +got:\n%s", diff)
+		}
+	})
+}